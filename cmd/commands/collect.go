@@ -26,11 +26,14 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -45,28 +48,77 @@ import (
 
 var (
 	// Collect command specific flags
-	samplingInterval time.Duration
-	outputPath       string
-	bufferSize       int
-	flushInterval    time.Duration
-	includeDisks     string
-	excludeDisks     string
-	includeNetworks  string
-	excludeNetworks  string
+	samplingInterval  time.Duration
+	outputPath        string
+	bufferSize        int
+	flushInterval     time.Duration
+	includeDisks      string
+	excludeDisks      string
+	includeNetworks   string
+	excludeNetworks   string
+	physicalOnly      bool
+	includeMounts     string
+	excludeMounts     string
+	includeFstypes    string
+	excludeFstypes    string
+	includeCollectors string
+	excludeCollectors string
+	sinks             []string
+	sinkQueueSize     int
+	onSchemaMismatch  string
+	fsyncPolicy       string
+	rotateSize        string
+	rotateInterval    string
+	fileNameTemplate  string
+	maxFiles          int
+	maxAge            time.Duration
+	compress          string
+	perCPU            bool
+	counterWrap32     bool
+	processPIDs       []int32
+	processNames      []string
+	processCmdlines   []string
+	topProcesses      int
+	cgroupRoot        string
+	cgroupPaths       []string
+	cgroupDiscover    bool
+	prometheusListen  string
+	influxURL         string
+	influxToken       string
+	influxOrg         string
+	influxBucket      string
+	influxSpoolPath   string
 )
 
 var collectCmd = &cobra.Command{
 	Use:   "collect",
 	Short: "Start UnoStat system monitoring",
 	Long: `Start UnoStat to monitor system performance metrics (CPU, RAM, Disk, Network).
-Data is collected and saved to CSV files.
+Data is collected and saved to CSV files by default; additional sinks can be
+selected with repeated --sink flags.
 
 Examples:
   # Run in foreground with default settings
   unostat collect
 
   # Custom interval and filters
-  unostat collect --interval 5s --include-disks "C:"`,
+  unostat collect --interval 5s --include-disks "C:"
+
+  # Physical devices only, skipping NVMe partitions and Wi-Fi interfaces
+  unostat collect --physical-only --include-disks "sd*,re:^nvme[0-9]+n[0-9]+$" --exclude-networks "wl*"
+
+  # Write to several sinks at once
+  unostat collect --sink csv --sink jsonl
+
+  # Rotate hourly and keep one week of files
+  unostat collect --rotate-interval hourly --file-template "{host}_{start}_{index}" --max-age 168h
+
+  # Also expose the latest metrics to Prometheus on http://<host>:9273/metrics
+  unostat collect --prometheus-listen :9273
+
+  # Monitor the host from a container with its root filesystem mounted at /host
+  # (use the host network namespace to see its interfaces)
+  unostat collect --host-root /host`,
 	RunE: runCollect,
 }
 
@@ -82,16 +134,101 @@ func init() {
 		"Buffer size for CSV writer")
 	collectCmd.Flags().DurationVar(&flushInterval, "flush-interval", config.DefaultFlushInterval,
 		"Flush interval for CSV writer")
+	collectCmd.Flags().StringVar(&onSchemaMismatch, "on-schema-mismatch", config.DefaultOnSchemaMismatch,
+		"What to do when the existing output CSV has a different header: rotate (start a new file) or fail")
+	collectCmd.Flags().StringVar(&fsyncPolicy, "fsync", config.FsyncNever,
+		"Fsync the CSV file: never, on-flush (after every flush) or every-N (after every N records)")
+
+	// Rotation and retention flags
+	collectCmd.Flags().StringVar(&rotateSize, "rotate-size", "150MB",
+		"Rotate output files when they reach this size (e.g., 50MB, 1GB)")
+	collectCmd.Flags().StringVar(&rotateInterval, "rotate-interval", "",
+		"Also rotate output files on wall clock boundaries: hourly or daily")
+	collectCmd.Flags().StringVar(&fileNameTemplate, "file-template", "",
+		"Name template for rotated files using {host}, {start}, {index}; the extension is kept (default: <output>_<N>)")
+	collectCmd.Flags().IntVar(&maxFiles, "max-files", 0,
+		"Keep at most this many output files, deleting the oldest written by this run (0 = unlimited)")
+	collectCmd.Flags().DurationVar(&maxAge, "max-age", 0,
+		"Delete output files written by this run once older than this, e.g. 168h (0 = unlimited)")
+	collectCmd.Flags().StringVar(&compress, "compress", config.CompressNone,
+		"Compress rotated output files in the background: none or gzip (*.csv.gz)")
+
+	collectCmd.Flags().StringArrayVar(&sinks, "sink", nil,
+		fmt.Sprintf("Sink to export metrics to, repeatable (default: %s; available: %s)",
+			exporter.DefaultSink, strings.Join(exporter.Registered(), ", ")))
+	collectCmd.Flags().IntVar(&sinkQueueSize, "sink-queue-size", config.DefaultSinkQueueSize,
+		"Number of snapshots buffered per sink before a slow sink starts dropping them")
+	collectCmd.Flags().StringVar(&prometheusListen, "prometheus-listen", "",
+		"Serve the latest metrics on <address>/metrics in Prometheus format (e.g., :9273); enables the prometheus sink")
+
+	// InfluxDB sink flags
+	collectCmd.Flags().StringVar(&influxURL, "influx-url", "",
+		"InfluxDB base URL for the influx sink (empty = write line protocol to <output>.lp)")
+	collectCmd.Flags().StringVar(&influxToken, "influx-token", "",
+		"InfluxDB API token (default: $INFLUX_TOKEN)")
+	collectCmd.Flags().StringVar(&influxOrg, "influx-org", "",
+		"InfluxDB organization")
+	collectCmd.Flags().StringVar(&influxBucket, "influx-bucket", "",
+		"InfluxDB bucket")
+	collectCmd.Flags().StringVar(&influxSpoolPath, "influx-spool", "",
+		"Spool file for batches that could not be delivered (default: <output>.spool.lp)")
+
+	// CPU flags
+	collectCmd.Flags().BoolVar(&perCPU, "per-cpu", false,
+		"Also collect utilization and iowait of every logical core, plus the busiest core")
+
+	// Process flags
+	collectCmd.Flags().Int32SliceVar(&processPIDs, "process-pid", nil,
+		"PID of a process to monitor (repeatable or comma-separated)")
+	collectCmd.Flags().StringArrayVar(&processNames, "process-name", nil,
+		"Monitor processes whose name matches this regular expression (repeatable)")
+	collectCmd.Flags().StringArrayVar(&processCmdlines, "process-cmdline", nil,
+		"Monitor processes whose command line contains this string (repeatable)")
+
+	collectCmd.Flags().IntVar(&topProcesses, "top-processes", 0,
+		"Record the N heaviest processes by CPU and RSS per interval to <output>.top.jsonl next to the CSV; requires the csv sink (0 = disabled)")
+
+	// Cgroup flags
+	collectCmd.Flags().StringVar(&cgroupRoot, "cgroup-root", "",
+		"Mountpoint of the cgroup v2 hierarchy (default <host-sys>/fs/cgroup)")
+	collectCmd.Flags().StringArrayVar(&cgroupPaths, "cgroup", nil,
+		"Cgroup to monitor, relative to --cgroup-root (e.g., system.slice/nginx.service; repeatable)")
+	collectCmd.Flags().BoolVar(&cgroupDiscover, "cgroup-discover", false,
+		"Monitor the cgroups of running containers (docker, containerd, CRI-O, podman)")
+
+	// Host flags
+	addHostFlags(collectCmd)
+
+	// Counter flags
+	collectCmd.Flags().BoolVar(&counterWrap32, "counter-wrap32", false,
+		"Treat decreasing disk and network counters below 2^32 as 32-bit wraparounds instead of resets")
 
 	// Filter flags
 	collectCmd.Flags().StringVar(&includeDisks, "include-disks", "",
-		"Comma-separated list of disk devices to monitor (empty = all)")
+		"Comma-separated list of disk devices to monitor: names, globs (sd*) or re:<regex> (empty = all)")
 	collectCmd.Flags().StringVar(&excludeDisks, "exclude-disks", "",
-		"Comma-separated list of disk devices to exclude")
+		"Comma-separated list of disk devices to exclude: names, globs (loop*) or re:<regex>")
 	collectCmd.Flags().StringVar(&includeNetworks, "include-networks", "",
-		"Comma-separated list of network interfaces to monitor (empty = all)")
+		"Comma-separated list of network interfaces to monitor: names, globs (eth*) or re:<regex> (empty = all)")
 	collectCmd.Flags().StringVar(&excludeNetworks, "exclude-networks", "",
-		"Comma-separated list of network interfaces to exclude")
+		"Comma-separated list of network interfaces to exclude: names, globs (veth*) or re:<regex>")
+	collectCmd.Flags().BoolVar(&physicalOnly, "physical-only", false,
+		"Skip virtual disks and network interfaces (loop, ram, zram, device-mapper, veth, bridges, docker...) detected via sysfs")
+	collectCmd.Flags().StringVar(&includeMounts, "include-mountpoints", "",
+		"Comma-separated list of filesystem mountpoints to monitor (empty = all)")
+	collectCmd.Flags().StringVar(&excludeMounts, "exclude-mountpoints", "",
+		"Comma-separated list of filesystem mountpoints to exclude")
+	collectCmd.Flags().StringVar(&includeFstypes, "include-fstypes", "",
+		"Comma-separated list of filesystem types to monitor (empty = all except pseudo filesystems)")
+	collectCmd.Flags().StringVar(&excludeFstypes, "exclude-fstypes", "",
+		"Comma-separated list of filesystem types to exclude")
+
+	// Collector selection flags
+	collectCmd.Flags().StringVar(&includeCollectors, "include-collectors", "",
+		fmt.Sprintf("Comma-separated list of collectors to enable (empty = all; available: %s)",
+			strings.Join(collector.Registered(), ", ")))
+	collectCmd.Flags().StringVar(&excludeCollectors, "exclude-collectors", "",
+		"Comma-separated list of collectors to disable")
 }
 
 // buildConfig creates a Config object from parsed flags.
@@ -101,6 +238,31 @@ func buildConfig() (*config.Config, error) {
 		OutputPath:       outputPath,
 		BufferSize:       bufferSize,
 		FlushInterval:    flushInterval,
+		Sinks:            sinks,
+		SinkQueueSize:    sinkQueueSize,
+		OnSchemaMismatch: onSchemaMismatch,
+		RotateInterval:   rotateInterval,
+		FileNameTemplate: fileNameTemplate,
+		MaxFiles:         maxFiles,
+		MaxAge:           maxAge,
+		Compress:         compress,
+		PerCPU:           perCPU,
+		CounterWrap32:    counterWrap32,
+		PhysicalOnly:     physicalOnly,
+		ProcessPIDs:      processPIDs,
+		ProcessNames:     processNames,
+		ProcessCmdlines:  processCmdlines,
+		TopProcesses:     topProcesses,
+		CgroupRoot:       cgroupRoot,
+		CgroupPaths:      cgroupPaths,
+		CgroupDiscover:   cgroupDiscover,
+		Host:             hostPaths,
+		PrometheusListen: prometheusListen,
+		InfluxURL:        influxURL,
+		InfluxToken:      influxToken,
+		InfluxOrg:        influxOrg,
+		InfluxBucket:     influxBucket,
+		InfluxSpoolPath:  influxSpoolPath,
 		LogLevel:         logLevel, // Access global var from root.go
 		LogFile:          logFile,  // Access global var from root.go
 		Timezone:         timezone, // Access global var from root.go
@@ -111,16 +273,61 @@ func buildConfig() (*config.Config, error) {
 		cfg.OutputPath = config.GetDefaultOutputPath()
 	}
 
+	size, err := config.ParseSize(rotateSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: --rotate-size: %w", err)
+	}
+	cfg.RotateSize = size
+
+	cfg.Fsync, cfg.FsyncEvery, err = config.ParseFsync(fsyncPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: --fsync: %w", err)
+	}
+
+	if cfg.InfluxToken == "" {
+		cfg.InfluxToken = os.Getenv("INFLUX_TOKEN")
+	}
+
 	// Parse filter lists
 	cfg.IncludeDisks = config.ParseCommaSeparated(includeDisks)
 	cfg.ExcludeDisks = config.ParseCommaSeparated(excludeDisks)
 	cfg.IncludeNetworks = config.ParseCommaSeparated(includeNetworks)
 	cfg.ExcludeNetworks = config.ParseCommaSeparated(excludeNetworks)
+	cfg.IncludeMountpoints = config.ParseCommaSeparated(includeMounts)
+	cfg.ExcludeMountpoints = config.ParseCommaSeparated(excludeMounts)
+	cfg.IncludeFstypes = config.ParseCommaSeparated(includeFstypes)
+	cfg.ExcludeFstypes = config.ParseCommaSeparated(excludeFstypes)
+	cfg.IncludeCollectors = config.ParseCommaSeparated(includeCollectors)
+	cfg.ExcludeCollectors = config.ParseCommaSeparated(excludeCollectors)
+	if len(cfg.Sinks) == 0 {
+		cfg.Sinks = []string{exporter.DefaultSink}
+	}
+	if cfg.PrometheusListen != "" && !slices.Contains(cfg.Sinks, "prometheus") {
+		cfg.Sinks = append(cfg.Sinks, "prometheus")
+	}
 
 	// Validate
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
+	if err := collector.ValidateNames(cfg.IncludeCollectors); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := collector.ValidateNames(cfg.ExcludeCollectors); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := exporter.ValidateNames(cfg.Sinks); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	// The rankings are written next to the CSV output only
+	if cfg.TopProcesses > 0 && !slices.Contains(cfg.Sinks, "csv") {
+		return nil, errors.New("invalid configuration: --top-processes requires the csv sink")
+	}
+
+	// Point gopsutil based collectors at the host as well
+	if err := cfg.Host.Setenv(); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
@@ -136,6 +343,7 @@ func runCollect(_ *cobra.Command, _ []string) error {
 
 	// Initialize logger
 	logger := initLogger(cfg)
+	slog.SetDefault(logger) // Collectors without a logger of their own log through the default
 
 	logger.Info("Starting UnoStat",
 		"version", version.Info(),
@@ -151,17 +359,22 @@ func runCollect(_ *cobra.Command, _ []string) error {
 	metricsChan := make(chan *metrics.Snapshot, 10)
 
 	// Create collector manager
-	collectorMgr := collector.NewManager(cfg, metricsChan, logger)
+	collectorMgr, err := collector.NewManager(cfg, metricsChan, logger)
+	if err != nil {
+		logger.Error("Failed to create collector manager", "error", err)
+		return err
+	}
 
-	// Create CSV exporter
-	csvExporter, err := exporter.NewCSVExporter(cfg, metricsChan, logger)
+	// Create sinks and the dispatcher that fans snapshots out to them
+	exporters, err := exporter.Build(cfg, logger)
 	if err != nil {
-		logger.Error("Failed to create CSV exporter", "error", err)
+		logger.Error("Failed to create exporters", "error", err)
 		return err
 	}
+	dispatcher := exporter.NewDispatcher(cfg, metricsChan, exporters, logger)
 	defer func() {
-		if err := csvExporter.Close(); err != nil {
-			logger.Error("Failed to close exporter", "error", err)
+		if err := dispatcher.Close(); err != nil {
+			logger.Error("Failed to close exporters", "error", err)
 		}
 	}()
 
@@ -179,7 +392,7 @@ func runCollect(_ *cobra.Command, _ []string) error {
 		cancel()
 	}()
 
-	logger.Info("UnoStat is running", "output", cfg.OutputPath)
+	logger.Info("UnoStat is running", "output", cfg.OutputPath, "sinks", cfg.Sinks)
 
 	// Use WaitGroup to track exporter goroutine
 	var wg sync.WaitGroup
@@ -188,8 +401,9 @@ func runCollect(_ *cobra.Command, _ []string) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := csvExporter.Start(ctx); err != nil {
+		if err := dispatcher.Start(ctx); err != nil {
 			logger.Error("Exporter stopped with error", "error", err)
+			cancel()
 		}
 	}()
 
@@ -49,15 +49,23 @@ Examples:
 
 func init() {
 	rootCmd.AddCommand(listDevicesCmd)
+	addHostFlags(listDevicesCmd)
 }
 
 func runListDevices(_ *cobra.Command, _ []string) error {
+	if err := hostPaths.Validate(); err != nil {
+		return err
+	}
+	if err := hostPaths.Setenv(); err != nil {
+		return err
+	}
+
 	fmt.Println("\n========================================")
 	fmt.Println("   UnoStat - Available Devices")
 	fmt.Println("========================================")
 
 	// List disk devices
-	disks, err := devices.ListDisks()
+	disks, err := devices.ListDisks(hostPaths.Root)
 	switch {
 	case err != nil:
 		fmt.Fprintf(os.Stderr, "Error listing disks: %v\n", err)
@@ -40,6 +40,9 @@ var (
 	logLevel string
 	logFile  string
 	timezone string
+
+	// Host filesystem flags (collect and list-devices)
+	hostPaths config.HostPaths
 )
 
 const (
@@ -76,6 +79,16 @@ func init() {
 		"Timezone for timestamps (e.g., 'Asia/Ho_Chi_Minh', 'Local')")
 }
 
+// addHostFlags registers the flags locating the filesystems of the monitored host on cmd.
+func addHostFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&hostPaths.Proc, "host-proc", "",
+		"Mountpoint of the host procfs when running in a container (default <host-root>/proc, else $HOST_PROC, else /proc)")
+	cmd.Flags().StringVar(&hostPaths.Sys, "host-sys", "",
+		"Mountpoint of the host sysfs when running in a container (default <host-root>/sys, else $HOST_SYS, else /sys)")
+	cmd.Flags().StringVar(&hostPaths.Root, "host-root", "",
+		"Mountpoint of the host root filesystem when running in a container (e.g., /host; default /)")
+}
+
 // InitLogger initializes and returns a slog.Logger based on the provided settings.
 // It is shared by all commands to ensure consistent logging format.
 func InitLogger(levelStr, fileStr string) *slog.Logger {
@@ -0,0 +1,343 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func init() {
+	Register("cgroup", func(cfg *config.Config) (Collector, error) {
+		if len(cfg.CgroupPaths) == 0 && !cfg.CgroupDiscover {
+			return nil, nil // Disabled
+		}
+		root := cfg.CgroupRoot
+		if root == "" {
+			root = filepath.Join(cfg.Host.SysRoot(), "fs", "cgroup")
+		}
+		c := NewCgroupCollector(root, cfg.CgroupPaths, cfg.CgroupDiscover)
+		c.devBlock = filepath.Join(cfg.Host.SysRoot(), "dev", "block")
+		return c, nil
+	})
+}
+
+// containerScope matches the cgroup of a container created by systemd-managed runtimes,
+// e.g. docker-<id>.scope or cri-containerd-<id>.scope.
+var containerScope = regexp.MustCompile(`^(docker|cri-containerd|crio|libpod)-([0-9a-f]{64})\.scope$`)
+
+// containerID matches the cgroup of a container created by the cgroupfs driver, e.g. docker/<id>.
+var containerID = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// maxDiscoveryDepth bounds the directory walk when discovering container cgroups.
+const maxDiscoveryDepth = 5
+
+// CgroupSample holds the resource usage of a single cgroup.
+type CgroupSample struct {
+	Name  string
+	Stats metrics.CgroupStats
+}
+
+// Apply stores the cgroup metrics in the snapshot.
+func (s CgroupSample) Apply(snapshot *metrics.Snapshot) {
+	if snapshot.Cgroups == nil {
+		snapshot.Cgroups = make(map[string]metrics.CgroupStats)
+	}
+	snapshot.Cgroups[s.Name] = s.Stats
+}
+
+// cgroupCounters holds the cumulative counters of a cgroup at one collection.
+type cgroupCounters struct {
+	cpu metrics.CgroupCPUCounters
+	io  map[string]metrics.CgroupIOCounters // Key: major:minor
+}
+
+// CgroupCollector collects CPU, memory, I/O and task metrics of cgroup v2 groups,
+// either configured explicitly or discovered from running containers.
+type CgroupCollector struct {
+	root     string                    // Mountpoint of the cgroup v2 hierarchy
+	paths    []string                  // Cgroups to monitor, relative to root
+	discover bool                      // Monitor the cgroups of running containers
+	prev     map[string]cgroupCounters // Counters of every cgroup at the previous collection
+	devices  map[string]string         // Resolved device names by major:minor
+	devBlock string                    // sysfs directory of block device links by major:minor
+}
+
+// NewCgroupCollector creates a new cgroup collector instance reading the hierarchy mounted at root.
+// paths are relative to root (e.g., "system.slice/nginx.service"); discover adds the cgroups of
+// docker, containerd, CRI-O and podman containers found at every collection.
+func NewCgroupCollector(root string, paths []string, discover bool) *CgroupCollector {
+	cleaned := make([]string, 0, len(paths))
+	for _, path := range paths {
+		cleaned = append(cleaned, strings.Trim(filepath.Clean("/"+path), "/"))
+	}
+	return &CgroupCollector{
+		root:     root,
+		paths:    cleaned,
+		discover: discover,
+		prev:     make(map[string]cgroupCounters),
+		devices:  make(map[string]string),
+		devBlock: filepath.Join(config.DefaultSysRoot, "dev", "block"),
+	}
+}
+
+// Init checks that root is a cgroup v2 hierarchy and takes the baseline counters.
+func (c *CgroupCollector) Init() error {
+	if _, err := os.Stat(filepath.Join(c.root, "cgroup.controllers")); err != nil {
+		return fmt.Errorf("no cgroup v2 hierarchy at %s: %w", c.root, err)
+	}
+	_, err := c.Collect(context.Background())
+	return err
+}
+
+// Collect gathers the usage of every monitored cgroup.
+// Returns one CgroupSample per cgroup seen at the previous collection as well;
+// new cgroups only store their baseline, and removed ones are forgotten.
+func (c *CgroupCollector) Collect(_ context.Context) ([]Sample, error) {
+	targets, err := c.targets()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	next := make(map[string]cgroupCounters, len(targets))
+	samples := make([]Sample, 0, len(targets))
+	for name, path := range targets {
+		dir := filepath.Join(c.root, path)
+		cpu, err := readCgroupCPU(dir)
+		if err != nil {
+			continue // Cgroup removed or cpu controller unavailable
+		}
+		cpu.Timestamp = now
+		current := cgroupCounters{cpu: cpu, io: readCgroupIO(dir, now)}
+		next[name] = current
+
+		prev, ok := c.prev[name]
+		if !ok {
+			continue // Baseline only
+		}
+
+		// A re-created cgroup has no valid delta; report it as N/A for this interval
+		if cgroupCountersReset(prev, current) {
+			samples = append(samples, CounterResetSample{Source: "cgroup/" + name})
+			continue
+		}
+
+		stats := metrics.CgroupStats{MemoryPercent: -1}
+		stats.CPU, stats.CPUUser, stats.CPUSystem, stats.ThrottledPercent, stats.ThrottledTime =
+			metrics.CalculateCgroupCPU(prev.cpu, current.cpu)
+		stats.MemoryCurrent, _ = readCgroupValue(dir, "memory.current")
+		if limit, ok := readCgroupValue(dir, "memory.max"); ok && limit > 0 {
+			stats.MemoryMax = limit
+			stats.MemoryPercent = float64(stats.MemoryCurrent) / float64(limit) * 100.0
+		}
+		stats.PIDs, _ = readCgroupValue(dir, "pids.current")
+
+		for dev, counters := range current.io {
+			if prevIO, ok := prev.io[dev]; ok {
+				if stats.IO == nil {
+					stats.IO = make(map[string]metrics.CgroupIOStats, len(current.io))
+				}
+				stats.IO[c.deviceName(dev)] = metrics.CalculateCgroupIO(prevIO, counters)
+			}
+		}
+
+		samples = append(samples, CgroupSample{Name: name, Stats: stats})
+	}
+	c.prev = next
+
+	return samples, nil
+}
+
+// cgroupCountersReset reports whether any CPU or I/O counter of a cgroup was reset.
+func cgroupCountersReset(prev, current cgroupCounters) bool {
+	if metrics.CgroupCPUCountersReset(prev.cpu, current.cpu) {
+		return true
+	}
+	for dev, counters := range current.io {
+		if prevIO, ok := prev.io[dev]; ok && metrics.CgroupIOCountersReset(prevIO, counters) {
+			return true
+		}
+	}
+	return false
+}
+
+// targets returns the cgroups to sample, keyed by display name, with their path relative to root.
+func (c *CgroupCollector) targets() (map[string]string, error) {
+	targets := make(map[string]string, len(c.paths))
+	for _, path := range c.paths {
+		targets[path] = path
+	}
+	if !c.discover {
+		return targets, nil
+	}
+
+	err := filepath.WalkDir(c.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == c.root {
+				return err
+			}
+			return nil // Cgroup removed during the walk
+		}
+		if !d.IsDir() || path == c.root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(c.root, path)
+		if err != nil {
+			return err
+		}
+		if name, ok := containerName(rel); ok {
+			targets[name] = filepath.ToSlash(rel)
+			return fs.SkipDir // Nested cgroups belong to the container
+		}
+		if strings.Count(rel, string(filepath.Separator)) >= maxDiscoveryDepth-1 {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover container cgroups: %w", err)
+	}
+	return targets, nil
+}
+
+// containerName returns the display name of a container cgroup (e.g., "docker-0123456789ab").
+// Reports false if rel is not a container cgroup.
+func containerName(rel string) (string, bool) {
+	base := filepath.Base(rel)
+	if m := containerScope.FindStringSubmatch(base); m != nil {
+		return m[1] + "-" + m[2][:12], true
+	}
+	if containerID.MatchString(base) && filepath.Base(filepath.Dir(rel)) == "docker" {
+		return "docker-" + base[:12], true
+	}
+	return "", false
+}
+
+// deviceName resolves a major:minor pair to its block device name, falling back to the pair itself.
+func (c *CgroupCollector) deviceName(dev string) string {
+	if name, ok := c.devices[dev]; ok {
+		return name
+	}
+	name := dev
+	if target, err := os.Readlink(filepath.Join(c.devBlock, dev)); err == nil {
+		name = filepath.Base(target)
+	}
+	c.devices[dev] = name
+	return name
+}
+
+// Name returns the collector name for logging purposes.
+func (c *CgroupCollector) Name() string {
+	return "Cgroup"
+}
+
+// readCgroupCPU parses the cpu.stat file of a cgroup.
+// Throttling fields are only present when the cpu controller is enabled.
+func readCgroupCPU(dir string) (metrics.CgroupCPUCounters, error) {
+	var counters metrics.CgroupCPUCounters
+	file, err := os.Open(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return counters, err
+	}
+	defer func() { _ = file.Close() }()
+
+	fields := map[string]*uint64{
+		"usage_usec":     &counters.UsageUsec,
+		"user_usec":      &counters.UserUsec,
+		"system_usec":    &counters.SystemUsec,
+		"nr_periods":     &counters.NrPeriods,
+		"nr_throttled":   &counters.NrThrottled,
+		"throttled_usec": &counters.ThrottledUsec,
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if field, known := fields[key]; ok && known {
+			*field, _ = strconv.ParseUint(value, 10, 64)
+		}
+	}
+	return counters, scanner.Err()
+}
+
+// readCgroupIO parses the io.stat file of a cgroup, keyed by major:minor.
+// Returns nil if the io controller is unavailable.
+func readCgroupIO(dir string, now time.Time) map[string]metrics.CgroupIOCounters {
+	data, err := os.ReadFile(filepath.Join(dir, "io.stat"))
+	if err != nil {
+		return nil
+	}
+
+	devices := make(map[string]metrics.CgroupIOCounters)
+	for line := range strings.Lines(string(data)) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		counters := metrics.CgroupIOCounters{Timestamp: now}
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				counters.ReadBytes = n
+			case "wbytes":
+				counters.WriteBytes = n
+			case "rios":
+				counters.ReadIOs = n
+			case "wios":
+				counters.WriteIOs = n
+			}
+		}
+		devices[fields[0]] = counters
+	}
+	return devices
+}
+
+// readCgroupValue reads a single-value cgroup file such as memory.current.
+// Reports false if the file is missing or holds "max".
+func readCgroupValue(dir, name string) (uint64, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,170 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+// Collector is a single source of metrics driven by the Manager.
+//
+// Init is called once before the collection loop starts and is the place to take
+// a baseline for delta-based metrics. Collect is called at every sampling interval
+// and returns the samples gathered since the previous call.
+type Collector interface {
+	Name() string
+	Init() error
+	Collect(ctx context.Context) ([]Sample, error)
+}
+
+// Sample is a typed measurement produced by a Collector.
+// Each sample knows how to merge itself into a metrics snapshot.
+type Sample interface {
+	Apply(snapshot *metrics.Snapshot)
+}
+
+// CounterResetSample flags a source whose counters were reset since the previous
+// collection, e.g. "disk/sda" or "system". Its metrics are omitted from the snapshot, except
+// where they share a sample with metrics that are still valid: swap rates are then zero.
+type CounterResetSample struct {
+	Source string
+}
+
+// Apply records the reset in the snapshot.
+func (s CounterResetSample) Apply(snapshot *metrics.Snapshot) {
+	snapshot.CounterResets = append(snapshot.CounterResets, s.Source)
+}
+
+// Factory builds a collector from the application configuration.
+// It returns a nil Collector (and nil error) when the collector is not applicable
+// for the given configuration.
+type Factory func(cfg *config.Config) (Collector, error)
+
+type registration struct {
+	name    string
+	factory Factory
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registration
+)
+
+// Register adds a collector factory to the registry under the given name.
+// Names are case-insensitive. Registering a name twice panics, as it indicates a programming error.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	key := strings.ToLower(name)
+	for _, r := range registry {
+		if r.name == key {
+			panic(fmt.Sprintf("collector %q already registered", name))
+		}
+	}
+	registry = append(registry, registration{name: key, factory: factory})
+}
+
+// Registered returns the names of all registered collectors, sorted alphabetically.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for _, r := range registry {
+		names = append(names, r.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateNames checks that every name refers to a registered collector.
+func ValidateNames(names []string) error {
+	available := Registered()
+	for _, name := range names {
+		key := strings.ToLower(name)
+		found := false
+		for _, a := range available {
+			if a == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown collector: %s (available: %s)", name, strings.Join(available, ", "))
+		}
+	}
+	return nil
+}
+
+// Build instantiates the collectors enabled by the configuration, in registration order.
+// An empty IncludeCollectors list enables every registered collector; ExcludeCollectors
+// always takes priority.
+func Build(cfg *config.Config) ([]Collector, error) {
+	registryMu.RLock()
+	regs := make([]registration, len(registry))
+	copy(regs, registry)
+	registryMu.RUnlock()
+
+	include := lowerSet(cfg.IncludeCollectors)
+	exclude := lowerSet(cfg.ExcludeCollectors)
+
+	collectors := make([]Collector, 0, len(regs))
+	for _, r := range regs {
+		if exclude[r.name] {
+			continue
+		}
+		if len(include) > 0 && !include[r.name] {
+			continue
+		}
+
+		c, err := r.factory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s collector: %w", r.name, err)
+		}
+		if c == nil {
+			continue
+		}
+		collectors = append(collectors, c)
+	}
+
+	return collectors, nil
+}
+
+// lowerSet converts a list of names into a lower-case lookup set.
+func lowerSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = true
+	}
+	return set
+}
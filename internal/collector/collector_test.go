@@ -26,51 +26,114 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/phuonguno98/unostat/internal/config"
 	"github.com/phuonguno98/unostat/pkg/metrics"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
 )
 
 func TestMemoryCollector(t *testing.T) {
 	c := NewMemoryCollector()
-	util, err := c.Collect()
+	samples, err := c.Collect(context.Background())
 	if err != nil {
 		t.Fatalf("MemoryCollector.Collect() error = %v", err)
 	}
+	if len(samples) != 1 {
+		t.Fatalf("MemoryCollector.Collect() returned %d samples, want 1", len(samples))
+	}
+	sample, ok := samples[0].(MemorySample)
+	if !ok {
+		t.Fatalf("sample type = %T, want MemorySample", samples[0])
+	}
+	util := sample.Utilization
 	if util < 0 || util > 100 {
 		t.Errorf("Memory utilization = %v, want [0, 100]", util)
 	}
+	detail := sample.Detail
+	if detail.Available == 0 {
+		t.Error("Available memory should be reported")
+	}
+	if detail.SwapUsed > detail.SwapTotal {
+		t.Errorf("Swap used %d exceeds total %d", detail.SwapUsed, detail.SwapTotal)
+	}
+	if detail.SwapIn != 0 || detail.SwapOut != 0 {
+		t.Errorf("Swap rates without a baseline = %v/%v, want 0", detail.SwapIn, detail.SwapOut)
+	}
 	if c.Name() != "Memory" {
 		t.Errorf("Name() = %v, want Memory", c.Name())
 	}
 }
 
+func TestMemoryCollector_SwapUnavailable(t *testing.T) {
+	orig := readSwapMemory
+	defer func() { readSwapMemory = orig }()
+	readSwapMemory = func() (*mem.SwapMemoryStat, error) {
+		return nil, errors.New("no swap accounting")
+	}
+
+	c := NewMemoryCollector()
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v, want nil when swap is not available", err)
+	}
+	samples, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(samples) != 1 {
+		t.Fatalf("Collect() returned %d samples, want 1", len(samples))
+	}
+	sample := samples[0].(MemorySample)
+	if sample.Utilization <= 0 {
+		t.Errorf("Memory utilization = %v, want > 0", sample.Utilization)
+	}
+	if sample.Detail.SwapTotal != 0 || sample.Detail.SwapUsed != 0 || sample.Detail.SwapIn != 0 {
+		t.Errorf("Swap fields = %+v, want zero", sample.Detail)
+	}
+}
+
 func TestCPUCollector(t *testing.T) {
 	c := NewCPUCollector()
 
 	// First run (baseline)
-	util, _, err := c.Collect()
+	samples, err := c.Collect(context.Background())
 	if err != nil {
 		t.Fatalf("First Collect() error = %v", err)
 	}
-	// Initial utilization should be 0 because it's first run
-	if util != 0 {
-		t.Logf("First run util = %v (expected 0/undefined baseline)", util)
+	if samples != nil {
+		t.Error("First Collect() should return no samples (baseline)")
 	}
 
 	// Sleep to allow some CPU time change
 	time.Sleep(100 * time.Millisecond)
 
 	// Second run (should have valid delta)
-	var iowait float64
-	util, iowait, err = c.Collect()
+	samples, err = c.Collect(context.Background())
 	if err != nil {
 		t.Fatalf("Second Collect() error = %v", err)
 	}
+	if len(samples) != 1 {
+		t.Fatalf("Second Collect() returned %d samples, want 1", len(samples))
+	}
+	sample, ok := samples[0].(CPUSample)
+	if !ok {
+		t.Fatalf("sample type = %T, want CPUSample", samples[0])
+	}
+	util, iowait := sample.Utilization, sample.IOWait
 
 	if util < 0 || util > 100 {
 		t.Errorf("CPU utilization = %v, want [0, 100]", util)
@@ -81,32 +144,69 @@ func TestCPUCollector(t *testing.T) {
 	}
 }
 
+func TestPerCPUCollector(t *testing.T) {
+	c := NewPerCPUCollector()
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	samples, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(samples) != 1 {
+		t.Fatalf("Collect() returned %d samples, want 1", len(samples))
+	}
+	sample := samples[0].(CPUSample)
+
+	if len(sample.Cores) == 0 {
+		t.Fatal("Per-CPU sample has no cores")
+	}
+	for core, stats := range sample.Cores {
+		if stats.Utilization < 0 || stats.Utilization > 100 {
+			t.Errorf("Core %s utilization = %v, want [0, 100]", core, stats.Utilization)
+		}
+	}
+
+	snapshot := &metrics.Snapshot{}
+	sample.Apply(snapshot)
+	if len(snapshot.Cores) != len(sample.Cores) {
+		t.Errorf("Apply() stored %d cores, want %d", len(snapshot.Cores), len(sample.Cores))
+	}
+}
+
 func TestDiskCollector(t *testing.T) {
-	c := NewDiskCollector(nil, nil)
+	c, err := NewDiskCollector(nil, nil)
+	if err != nil {
+		t.Fatalf("NewDiskCollector() error = %v", err)
+	}
 
 	// First run
-	stats, err := c.Collect()
+	samples, err := c.Collect(context.Background())
 	if err != nil {
 		t.Fatalf("First Collect() error = %v", err)
 	}
-	if stats != nil {
-		t.Error("First Collect() should return nil stats (baseline)")
+	if len(samples) != 0 {
+		t.Error("First Collect() should return no samples (baseline)")
 	}
 
 	time.Sleep(100 * time.Millisecond)
 
 	// Second run
-	stats, err = c.Collect()
+	samples, err = c.Collect(context.Background())
 	if err != nil {
 		t.Fatalf("Second Collect() error = %v", err)
 	}
-	if stats == nil {
-		t.Fatal("Second Collect() returned nil stats")
-	}
 
-	for name, stat := range stats {
-		if stat.Utilization < 0 { // Can exceed 100 technically
-			t.Errorf("Disk %s Util = %v, want >= 0", name, stat.Utilization)
+	for _, s := range samples {
+		sample, ok := s.(DiskSample)
+		if !ok {
+			t.Fatalf("sample type = %T, want DiskSample", s)
+		}
+		if sample.Stats.Utilization < 0 { // Can exceed 100 technically
+			t.Errorf("Disk %s Util = %v, want >= 0", sample.Device, sample.Stats.Utilization)
 		}
 	}
 
@@ -116,30 +216,37 @@ func TestDiskCollector(t *testing.T) {
 }
 
 func TestNetworkCollector(t *testing.T) {
-	c := NewNetworkCollector(nil, nil)
+	c, err := NewNetworkCollector(nil, nil)
+	if err != nil {
+		t.Fatalf("NewNetworkCollector() error = %v", err)
+	}
 
 	// First run
-	stats, err := c.Collect()
+	samples, err := c.Collect(context.Background())
 	if err != nil {
 		t.Fatalf("First Collect() error = %v", err)
 	}
-	if stats != nil {
-		t.Error("First Collect() should return nil stats (baseline)")
+	if len(samples) != 0 {
+		t.Error("First Collect() should return no samples (baseline)")
 	}
 
 	time.Sleep(100 * time.Millisecond)
 
 	// Second run
-	stats, err = c.Collect()
+	samples, err = c.Collect(context.Background())
 	if err != nil {
 		t.Fatalf("Second Collect() error = %v", err)
 	}
 
 	// Network stats might be empty if no traffic or interfaces found/filtered
 	// But it shouldn't error.
-	for name, stat := range stats {
-		if stat.Bandwidth < 0 {
-			t.Errorf("Net %s BW = %v, want >= 0", name, stat.Bandwidth)
+	for _, s := range samples {
+		sample, ok := s.(NetworkSample)
+		if !ok {
+			t.Fatalf("sample type = %T, want NetworkSample", s)
+		}
+		if sample.Stats.Bandwidth < 0 {
+			t.Errorf("Net %s BW = %v, want >= 0", sample.Interface, sample.Stats.Bandwidth)
 		}
 	}
 
@@ -157,7 +264,10 @@ func TestManager_StartStop(t *testing.T) {
 		IncludeNetworks:  nil,
 	}
 
-	mgr := NewManager(cfg, metricsChan, logger)
+	mgr, err := NewManager(cfg, metricsChan, logger)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
 	defer cancel()
@@ -273,11 +383,57 @@ func TestDiskCollector_ShouldMonitor(t *testing.T) {
 			device:  "sdc",
 			want:    false,
 		},
+		// Glob and regex patterns
+		{
+			name:    "Include Glob (Match)",
+			include: []string{"/dev/sd*"},
+			exclude: nil,
+			device:  "sdc",
+			want:    true,
+		},
+		{
+			name:    "Include Glob (No Match)",
+			include: []string{"sd?"},
+			exclude: nil,
+			device:  "nvme0n1",
+			want:    false,
+		},
+		{
+			name:    "Exclude Glob",
+			include: nil,
+			exclude: []string{"loop*"},
+			device:  "loop7",
+			want:    false,
+		},
+		{
+			name:    "Include Regex (Match)",
+			include: []string{"re:^nvme[0-9]+n1$"},
+			exclude: nil,
+			device:  "nvme1n1",
+			want:    true,
+		},
+		{
+			name:    "Include Regex (No Match)",
+			include: []string{"re:^nvme[0-9]+n1$"},
+			exclude: nil,
+			device:  "nvme1n1p1",
+			want:    false,
+		},
+		{
+			name:    "Exclude Regex Overrides Include Glob",
+			include: []string{"sd*"},
+			exclude: []string{"re:^sd[a-b]$"},
+			device:  "sdb",
+			want:    false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := NewDiskCollector(tt.include, tt.exclude)
+			c, err := NewDiskCollector(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewDiskCollector() error = %v", err)
+			}
 			if got := c.shouldMonitor(tt.device); got != tt.want {
 				t.Errorf("shouldMonitor(%q) = %v, want %v", tt.device, got, tt.want)
 			}
@@ -320,11 +476,19 @@ func TestNetworkCollector_ShouldMonitor(t *testing.T) {
 		{"Exclude", nil, []string{"eth0"}, "eth0", false},
 		{"Include Match", []string{"eth0"}, nil, "eth0", true},
 		{"Include No Match", []string{"eth0"}, nil, "eth1", false},
+		{"Include Glob", []string{"eth*"}, nil, "eth1", true},
+		{"Exclude Glob", nil, []string{"veth*", "docker?"}, "vethab12cd", false},
+		{"Exclude Glob No Match", nil, []string{"veth*", "docker?"}, "docker10", true},
+		{"Include Regex", []string{"re:^(eth|ens)[0-9]+$"}, nil, "ens3", true},
+		{"Include Regex No Match", []string{"re:^(eth|ens)[0-9]+$"}, nil, "ens3.100", false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := NewNetworkCollector(tt.include, tt.exclude)
+			c, err := NewNetworkCollector(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewNetworkCollector() error = %v", err)
+			}
 			if got := c.shouldMonitor(tt.iface); got != tt.want {
 				t.Errorf("shouldMonitor(%q) = %v, want %v", tt.iface, got, tt.want)
 			}
@@ -332,6 +496,134 @@ func TestNetworkCollector_ShouldMonitor(t *testing.T) {
 	}
 }
 
+func TestDeviceFilters_InvalidPattern(t *testing.T) {
+	for _, pattern := range []string{"re:sd(a", "sd[a"} {
+		if _, err := NewDiskCollector([]string{pattern}, nil); err == nil {
+			t.Errorf("NewDiskCollector(%q) should fail", pattern)
+		}
+		if _, err := NewNetworkCollector(nil, []string{pattern}); err == nil {
+			t.Errorf("NewNetworkCollector(%q) should fail", pattern)
+		}
+	}
+
+	// Build is where the configured patterns are validated
+	if _, err := Build(&config.Config{IncludeCollectors: []string{"disk"}, ExcludeDisks: []string{"re:loop(0"}}); err == nil {
+		t.Error("Build() should fail for an invalid disk pattern")
+	}
+	if _, err := Build(&config.Config{IncludeCollectors: []string{"network"}, IncludeNetworks: []string{"eth[0"}}); err == nil {
+		t.Error("Build() should fail for an invalid network pattern")
+	}
+	// Names without wildcards are matched literally, whatever they contain
+	if _, err := Build(&config.Config{IncludeCollectors: []string{"disk", "network"}, IncludeDisks: []string{`C:\`}, IncludeNetworks: []string{"re:^eth[0-9]+$"}}); err != nil {
+		t.Errorf("Build() error = %v for valid patterns", err)
+	}
+}
+
+func TestDeviceFilters_PhysicalOnly(t *testing.T) {
+	sysRoot := t.TempDir()
+	links := map[string]string{
+		"class/block/sda":      "../../devices/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0/block/sda",
+		"class/block/loop0":    "../../devices/virtual/block/loop0",
+		"class/block/dm-0":     "../../devices/virtual/block/dm-0",
+		"class/net/eth0":       "../../devices/pci0000:00/0000:00:03.0/net/eth0",
+		"class/net/docker0":    "../../devices/virtual/net/docker0",
+		"class/net/veth1a2b3c": "../../devices/virtual/net/veth1a2b3c",
+	}
+	for name, target := range links {
+		link := filepath.Join(sysRoot, name)
+		if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, link); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	disk, err := NewDiskCollector(nil, nil)
+	if err != nil {
+		t.Fatalf("NewDiskCollector() error = %v", err)
+	}
+	disk.physicalOnly = true
+	disk.sysRoot = sysRoot
+
+	network, err := NewNetworkCollector(nil, nil)
+	if err != nil {
+		t.Fatalf("NewNetworkCollector() error = %v", err)
+	}
+	network.physicalOnly = true
+	network.sysRoot = sysRoot
+
+	tests := []struct {
+		name    string
+		monitor func(string) bool
+		device  string
+		want    bool
+	}{
+		{"Physical Disk", disk.shouldMonitor, "sda", true},
+		{"Loop Device", disk.shouldMonitor, "loop0", false},
+		{"Device Mapper", disk.shouldMonitor, "dm-0", false},
+		{"Disk Without Sysfs Entry", disk.shouldMonitor, "nvme0n1", true},
+		{"Zram Without Sysfs Entry", disk.shouldMonitor, "zram0", false},
+		{"Physical Interface", network.shouldMonitor, "eth0", true},
+		{"Docker Bridge", network.shouldMonitor, "docker0", false},
+		{"Veth Pair", network.shouldMonitor, "veth1a2b3c", false},
+		{"Interface Without Sysfs Entry", network.shouldMonitor, "en0", true},
+		{"Bridge Without Sysfs Entry", network.shouldMonitor, "br-1f2e3d", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.monitor(tt.device); got != tt.want {
+				t.Errorf("shouldMonitor(%q) = %v, want %v", tt.device, got, tt.want)
+			}
+		})
+	}
+
+	// Filters still apply to physical devices
+	disk.filter, _ = newNameFilter(nil, []string{"sd*"})
+	if disk.shouldMonitor("sda") {
+		t.Error("shouldMonitor(sda) = true with sd* excluded, want false")
+	}
+}
+
+func TestNetworkCollector_IsLoopback(t *testing.T) {
+	orig := listInterfaces
+	defer func() { listInterfaces = orig }()
+
+	calls := 0
+	listInterfaces = func() (net.InterfaceStatList, error) {
+		calls++
+		return net.InterfaceStatList{
+			{Name: "lo1", Flags: []string{"up", "loopback", "running"}},
+			{Name: "eth0", Flags: []string{"up", "broadcast", "multicast"}},
+		}, nil
+	}
+
+	c, err := NewNetworkCollector(nil, nil)
+	if err != nil {
+		t.Fatalf("NewNetworkCollector() error = %v", err)
+	}
+
+	tests := []struct {
+		iface string
+		want  bool
+	}{
+		{"lo", true},
+		{"lo1", true},
+		{"eth0", false},
+		{"wlan0", false},
+		{"wlan0", false},
+	}
+	for _, tt := range tests {
+		if got := c.isLoopback(tt.iface); got != tt.want {
+			t.Errorf("isLoopback(%q) = %v, want %v", tt.iface, got, tt.want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("interfaces listed %d times, want 2 (once per unknown interface)", calls)
+	}
+}
+
 func TestManager_Lifecycle(t *testing.T) {
 	// Save/Restore original delay
 	origDelay := startUpDelay
@@ -343,7 +635,10 @@ func TestManager_Lifecycle(t *testing.T) {
 	}
 	ch := make(chan *metrics.Snapshot, 10)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
-	m := NewManager(cfg, ch, logger)
+	m, err := NewManager(cfg, ch, logger)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -380,3 +675,684 @@ func TestManager_Lifecycle(t *testing.T) {
 		t.Error("Start did not return after cancellation")
 	}
 }
+
+// fakeCollector is a Collector returning a fixed memory sample.
+type fakeCollector struct {
+	name    string
+	initErr error
+	value   float64
+}
+
+func (f *fakeCollector) Name() string { return f.name }
+func (f *fakeCollector) Init() error  { return f.initErr }
+func (f *fakeCollector) Collect(_ context.Context) ([]Sample, error) {
+	return []Sample{MemorySample{Utilization: f.value}}, nil
+}
+
+func TestBuild_IncludeExclude(t *testing.T) {
+	// Procfs-based collectors are Linux only
+	defaults := []string{"CPU", "Disk", "Filesystem", "Memory", "Network"}
+	if runtime.GOOS == "linux" {
+		defaults = []string{"CPU", "Disk", "Filesystem", "Memory", "Netstat", "Network", "PSI", "System"}
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{"Default (All)", nil, nil, defaults}, // Process, top and cgroup need configuration
+		{"Include Subset", []string{"cpu", "Memory"}, nil, []string{"CPU", "Memory"}},
+		{"Exclude", nil, []string{"disk", "netstat", "network", "psi", "system"}, []string{"CPU", "Filesystem", "Memory"}},
+		{"Exclude Overrides Include", []string{"cpu", "disk"}, []string{"disk"}, []string{"CPU"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collectors, err := Build(&config.Config{IncludeCollectors: tt.include, ExcludeCollectors: tt.exclude})
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if len(collectors) != len(tt.want) {
+				t.Fatalf("Build() returned %d collectors, want %d", len(collectors), len(tt.want))
+			}
+			for i, c := range collectors {
+				if c.Name() != tt.want[i] {
+					t.Errorf("collectors[%d] = %q, want %q", i, c.Name(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateNames(t *testing.T) {
+	if err := ValidateNames([]string{"cpu", "NETWORK"}); err != nil {
+		t.Errorf("ValidateNames() unexpected error = %v", err)
+	}
+	if err := ValidateNames([]string{"gpu"}); err == nil {
+		t.Error("ValidateNames() expected error for unknown collector")
+	}
+}
+
+func TestManager_CustomCollectors(t *testing.T) {
+	origDelay := startUpDelay
+	startUpDelay = 10 * time.Millisecond
+	defer func() { startUpDelay = origDelay }()
+
+	cfg := &config.Config{SamplingInterval: 20 * time.Millisecond}
+	ch := make(chan *metrics.Snapshot, 10)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	collectors := []Collector{
+		&fakeCollector{name: "broken", initErr: errors.New("unavailable"), value: 99},
+		&fakeCollector{name: "fake", value: 42},
+	}
+	m := NewManagerWithCollectors(cfg, collectors, ch, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = m.Start(ctx) }()
+
+	select {
+	case snap := <-ch:
+		if snap.Memory != 42 {
+			t.Errorf("snapshot.Memory = %v, want 42", snap.Memory)
+		}
+		if snap.CPUWait != -1 {
+			t.Errorf("snapshot.CPUWait = %v, want -1 when CPU collector is disabled", snap.CPUWait)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for metrics")
+	}
+}
+
+func TestFilesystemCollector(t *testing.T) {
+	origPartitions, origUsage := fsPartitions, fsUsage
+	defer func() { fsPartitions, fsUsage = origPartitions, origUsage }()
+
+	fsPartitions = func(_ context.Context, _ bool) ([]disk.PartitionStat, error) {
+		return []disk.PartitionStat{
+			{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+			{Device: "proc", Mountpoint: "/proc", Fstype: "proc"},
+			{Device: "tmpfs", Mountpoint: "/tmp", Fstype: "tmpfs"},
+			{Device: "/dev/sdb1", Mountpoint: "/data", Fstype: "xfs"},
+			{Device: "/dev/sdc1", Mountpoint: "/data", Fstype: "ext4"}, // Stacked on top of /dev/sdb1
+			{Device: "server:/export", Mountpoint: "/mnt/nfs", Fstype: "nfs4"},
+		}, nil
+	}
+	fsUsage = func(_ context.Context, path string) (*disk.UsageStat, error) {
+		if path == "/mnt/nfs" {
+			return nil, errors.New("stale file handle")
+		}
+		return &disk.UsageStat{Path: path, Total: 1000, Used: 250, Free: 750, UsedPercent: 25,
+			InodesTotal: 100, InodesUsed: 10, InodesFree: 90, InodesUsedPercent: 10}, nil
+	}
+
+	c := NewFilesystemCollector(nil, nil, nil, nil)
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	samples, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	snapshot := &metrics.Snapshot{}
+	for _, s := range samples {
+		s.Apply(snapshot)
+	}
+	if len(snapshot.Filesystems) != 3 {
+		t.Fatalf("Collected %d filesystems, want 3 (/, /tmp, /data): %v", len(snapshot.Filesystems), snapshot.Filesystems)
+	}
+	if got := snapshot.Filesystems["/data"]; got.Device != "/dev/sdc1" || got.Fstype != "ext4" {
+		t.Errorf("/data = %s (%s), want the visible mount /dev/sdc1 (ext4)", got.Device, got.Fstype)
+	}
+	if got := snapshot.Filesystems["/"]; got.Used != 250 || got.InodesFree != 90 || got.UsedPercent != 25 {
+		t.Errorf("/ = %+v, want usage values", got)
+	}
+
+	if c.Name() != "Filesystem" {
+		t.Errorf("Name() = %v, want Filesystem", c.Name())
+	}
+
+	// In a container the host mountpoints are resolved under the host root, but reported as-is
+	var paths []string
+	fsUsage = func(_ context.Context, path string) (*disk.UsageStat, error) {
+		paths = append(paths, path)
+		return &disk.UsageStat{Path: path, Total: 1000}, nil
+	}
+	c.rootFS = "/host"
+	if samples, err = c.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if want := []string{"/host", "/host/tmp", "/host/data", "/host/mnt/nfs"}; !slices.Equal(paths, want) {
+		t.Errorf("Usage paths = %v, want %v", paths, want)
+	}
+	snapshot = &metrics.Snapshot{}
+	for _, s := range samples {
+		s.Apply(snapshot)
+	}
+	if _, ok := snapshot.Filesystems["/data"]; !ok {
+		t.Errorf("Filesystems = %v, want host mountpoints", snapshot.Filesystems)
+	}
+}
+
+func TestFilesystemCollector_ShouldMonitor(t *testing.T) {
+	tests := []struct {
+		name           string
+		includeMounts  []string
+		excludeMounts  []string
+		includeFstypes []string
+		excludeFstypes []string
+		mountpoint     string
+		fstype         string
+		want           bool
+	}{
+		{"Default", nil, nil, nil, nil, "/", "ext4", true},
+		{"Pseudo Skipped", nil, nil, nil, nil, "/sys", "sysfs", false},
+		{"Pseudo Included Explicitly", nil, nil, []string{"sysfs"}, nil, "/sys", "sysfs", true},
+		{"Include Mountpoint", []string{"/var/log"}, nil, nil, nil, "/", "ext4", false},
+		{"Exclude Mountpoint", nil, []string{"/boot"}, nil, nil, "/boot", "ext4", false},
+		{"Include Fstype", nil, nil, []string{"xfs"}, nil, "/", "ext4", false},
+		{"Exclude Fstype", nil, nil, nil, []string{"tmpfs"}, "/tmp", "tmpfs", false},
+		{"Exclude Wins", []string{"/"}, nil, nil, []string{"ext4"}, "/", "ext4", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewFilesystemCollector(tt.includeMounts, tt.excludeMounts, tt.includeFstypes, tt.excludeFstypes)
+			if got := c.shouldMonitor(tt.mountpoint, tt.fstype); got != tt.want {
+				t.Errorf("shouldMonitor(%q, %q) = %v, want %v", tt.mountpoint, tt.fstype, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessCollector_SelfPID(t *testing.T) {
+	c, err := NewProcessCollector([]int32{int32(os.Getpid())}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewProcessCollector() error = %v", err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	samples, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(samples) != 1 {
+		t.Fatalf("Collect() returned %d samples, want 1", len(samples))
+	}
+	sample := samples[0].(ProcessSample)
+	if sample.Selector != "pid:"+strconv.Itoa(os.Getpid()) || sample.Stats.Count != 1 {
+		t.Errorf("sample = %+v, want the test process", sample)
+	}
+	if sample.Stats.RSS == 0 || sample.Stats.Threads == 0 {
+		t.Errorf("RSS = %d, Threads = %d, want non-zero", sample.Stats.RSS, sample.Stats.Threads)
+	}
+}
+
+func TestProcessCollector_Restart(t *testing.T) {
+	origList, origRead := listProcesses, readProcess
+	defer func() { listProcesses, readProcess = origList, origRead }()
+
+	// The service runs as PID 100, then restarts as PID 200
+	running := map[int32]processCounters{
+		100: {CreateTime: 1, RSS: 100 << 20, Threads: 4, FDs: 10, IO: metrics.ProcessIOStats{CPUTime: 10, ReadBytes: 1000}},
+		300: {CreateTime: 1, RSS: 1 << 20, Threads: 1, FDs: 3},
+	}
+	names := map[int32]string{100: "app-server", 200: "app-server", 300: "sidecar"}
+	listProcesses = func(_ context.Context, withCmdline bool) ([]processInfo, error) {
+		var infos []processInfo
+		for pid := range running {
+			info := processInfo{PID: pid, Name: names[pid]}
+			if withCmdline {
+				info.Cmdline = "/usr/bin/" + names[pid] + " --port 8080"
+			}
+			infos = append(infos, info)
+		}
+		return infos, nil
+	}
+	readProcess = func(_ context.Context, pid int32) (processCounters, error) {
+		c, ok := running[pid]
+		if !ok {
+			return processCounters{}, errors.New("process not found")
+		}
+		return c, nil
+	}
+
+	c, err := NewProcessCollector([]int32{100}, []string{"^app-"}, []string{"sidecar"})
+	if err != nil {
+		t.Fatalf("NewProcessCollector() error = %v", err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	collect := func() map[string]metrics.ProcessStats {
+		t.Helper()
+		samples, err := c.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect() error = %v", err)
+		}
+		snapshot := &metrics.Snapshot{}
+		for _, s := range samples {
+			s.Apply(snapshot)
+		}
+		return snapshot.Processes
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	running[100] = processCounters{CreateTime: 1, RSS: 120 << 20, Threads: 4, FDs: 12, IO: metrics.ProcessIOStats{CPUTime: 10.5, ReadBytes: 3000}}
+	procs := collect()
+	for _, key := range []string{"pid:100", "name:^app-", "cmdline:sidecar"} {
+		if procs[key].Count != 1 {
+			t.Errorf("%s count = %d, want 1", key, procs[key].Count)
+		}
+	}
+	if got := procs["name:^app-"]; got.CPU <= 0 || got.ReadBytes <= 0 || got.RSS != 120<<20 || got.FDs != 12 {
+		t.Errorf("name:^app- = %+v, want CPU and read rates against the baseline", got)
+	}
+
+	// Restart: the PID selector loses the process, the name selector follows it
+	delete(running, 100)
+	running[200] = processCounters{CreateTime: 2, RSS: 80 << 20, Threads: 2, FDs: 8, IO: metrics.ProcessIOStats{CPUTime: 0.1}}
+	procs = collect()
+	if _, ok := procs["pid:100"]; ok {
+		t.Error("pid:100 should not be reported after the process exited")
+	}
+	if got := procs["name:^app-"]; got.Count != 1 || got.RSS != 80<<20 || got.CPU != 0 {
+		t.Errorf("name:^app- after restart = %+v, want the new process without rates", got)
+	}
+}
+
+func TestTopCollector(t *testing.T) {
+	origList := listProcessUsage
+	defer func() { listProcessUsage = origList }()
+
+	usage := []processUsage{
+		{PID: 1, Name: "init", CreateTime: 1, CPUTime: 1.0, RSS: 10 << 20},
+		{PID: 20, Name: "db", CreateTime: 1, CPUTime: 50.0, RSS: 900 << 20},
+		{PID: 30, Name: "web", CreateTime: 1, CPUTime: 20.0, RSS: 300 << 20},
+		{PID: 40, Name: "kworker", CreateTime: 1},
+	}
+	listProcessUsage = func(_ context.Context) ([]processUsage, error) {
+		return usage, nil
+	}
+
+	c := NewTopCollector(2)
+	samples, err := c.Collect(context.Background())
+	if err != nil || len(samples) != 0 {
+		t.Fatalf("First Collect() = %v, %v; want baseline only", samples, err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	usage = []processUsage{
+		{PID: 1, Name: "init", CreateTime: 1, CPUTime: 1.0, RSS: 10 << 20},
+		{PID: 20, Name: "db", CreateTime: 1, CPUTime: 50.001, RSS: 900 << 20},
+		{PID: 30, Name: "web", CreateTime: 1, CPUTime: 20.005, RSS: 300 << 20},
+		{PID: 40, Name: "kworker", CreateTime: 1},
+		{PID: 50, Name: "batch", CreateTime: 2, CPUTime: 99.0, RSS: 500 << 20}, // New: ranked by RSS only
+	}
+	samples, err = c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	snapshot := &metrics.Snapshot{}
+	for _, s := range samples {
+		s.Apply(snapshot)
+	}
+	top := snapshot.TopProcesses
+	if top == nil {
+		t.Fatal("TopProcesses not set")
+	}
+
+	var cpuNames, rssNames []string
+	for _, p := range top.ByCPU {
+		cpuNames = append(cpuNames, p.Name)
+	}
+	for _, p := range top.ByRSS {
+		rssNames = append(rssNames, p.Name)
+	}
+	if !slices.Equal(cpuNames, []string{"web", "db"}) {
+		t.Errorf("ByCPU = %v, want [web db]", cpuNames)
+	}
+	if !slices.Equal(rssNames, []string{"db", "batch"}) {
+		t.Errorf("ByRSS = %v, want [db batch]", rssNames)
+	}
+	if top.ByCPU[0].Value <= top.ByCPU[1].Value || top.ByRSS[0].Value != 900<<20 {
+		t.Errorf("Unexpected values: %+v", top)
+	}
+}
+
+func TestCgroupCollector(t *testing.T) {
+	root := t.TempDir()
+	devRoot := t.TempDir()
+
+	if err := os.Symlink("../../devices/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0/block/sda", filepath.Join(devRoot, "8:0")); err != nil {
+		t.Fatal(err)
+	}
+
+	const id = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	app := filepath.Join(root, "system.slice", "app.service")
+	container := filepath.Join(root, "system.slice", "docker-"+id+".scope")
+	writeFiles := func(dir string, files map[string]string) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writeFiles(root, map[string]string{"cgroup.controllers": "cpu io memory pids\n"})
+	writeFiles(filepath.Join(container, "init"), map[string]string{"cpu.stat": "usage_usec 1\n"}) // Nested, not a container
+	writeFiles(filepath.Join(root, "user.slice"), map[string]string{"cpu.stat": "usage_usec 1\n"})
+
+	writeFiles(app, map[string]string{
+		"cpu.stat":       "usage_usec 1000\nuser_usec 600\nsystem_usec 400\nnr_periods 10\nnr_throttled 0\nthrottled_usec 0\n",
+		"memory.current": "104857600\n",
+		"memory.max":     "419430400\n",
+		"pids.current":   "7\n",
+		"io.stat":        "8:0 rbytes=1000 wbytes=0 rios=10 wios=0 dbytes=0 dios=0\n259:0 rbytes=0 wbytes=0 rios=0 wios=0\n",
+	})
+	writeFiles(container, map[string]string{
+		"cpu.stat":       "usage_usec 5000\nuser_usec 5000\nsystem_usec 0\n",
+		"memory.current": "1048576\n",
+		"memory.max":     "max\n",
+	})
+
+	c := NewCgroupCollector(root, []string{"/system.slice/app.service/"}, true)
+	c.devBlock = devRoot
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	writeFiles(app, map[string]string{
+		"cpu.stat": "usage_usec 9000\nuser_usec 6600\nsystem_usec 2400\nnr_periods 30\nnr_throttled 5\nthrottled_usec 2000\n",
+		"io.stat":  "8:0 rbytes=5096 wbytes=8192 rios=14 wios=2\n259:0 rbytes=0 wbytes=4096 rios=0 wios=1\n",
+	})
+	writeFiles(container, map[string]string{"cpu.stat": "usage_usec 9000\nuser_usec 9000\nsystem_usec 0\n"})
+
+	samples, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	snapshot := &metrics.Snapshot{}
+	for _, s := range samples {
+		s.Apply(snapshot)
+	}
+
+	if names := slices.Sorted(maps.Keys(snapshot.Cgroups)); !slices.Equal(names, []string{"docker-0123456789ab", "system.slice/app.service"}) {
+		t.Fatalf("Cgroups = %v, want [docker-0123456789ab system.slice/app.service]", names)
+	}
+
+	stats := snapshot.Cgroups["system.slice/app.service"]
+	if stats.CPU <= 0 || stats.CPUUser <= stats.CPUSystem || stats.ThrottledTime <= 0 {
+		t.Errorf("Unexpected CPU stats: %+v", stats)
+	}
+	if stats.ThrottledPercent != 25.0 {
+		t.Errorf("ThrottledPercent = %v, want 25", stats.ThrottledPercent)
+	}
+	if stats.MemoryCurrent != 100<<20 || stats.MemoryMax != 400<<20 || stats.MemoryPercent != 25.0 || stats.PIDs != 7 {
+		t.Errorf("Unexpected memory/pids stats: %+v", stats)
+	}
+	if names := slices.Sorted(maps.Keys(stats.IO)); !slices.Equal(names, []string{"259:0", "sda"}) {
+		t.Errorf("IO devices = %v, want [259:0 sda]", names)
+	}
+	if sda := stats.IO["sda"]; sda.ReadBytes <= 0 || sda.WriteIOPS <= 0 {
+		t.Errorf("Unexpected sda I/O: %+v", sda)
+	}
+
+	docker := snapshot.Cgroups["docker-0123456789ab"]
+	if docker.CPU <= 0 || docker.MemoryMax != 0 || docker.MemoryPercent != -1 || docker.IO != nil {
+		t.Errorf("Unexpected container stats: %+v", docker)
+	}
+
+	// A removed container is forgotten
+	if err := os.RemoveAll(container); err != nil {
+		t.Fatal(err)
+	}
+	samples, err = c.Collect(context.Background())
+	if err != nil || len(samples) != 1 {
+		t.Errorf("Collect() after removal = %d samples, %v; want 1", len(samples), err)
+	}
+
+	if err := NewCgroupCollector(t.TempDir(), nil, true).Init(); err == nil {
+		t.Error("Init() expected error without cgroup.controllers")
+	}
+}
+
+func TestPSICollector(t *testing.T) {
+	procRoot := t.TempDir()
+	pressureDir := filepath.Join(procRoot, "pressure")
+	if err := os.Mkdir(pressureDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writePressure := func(resource, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(pressureDir, resource), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// cpu without a full line (kernels before 5.13); no io file
+	writePressure("cpu", "some avg10=1.50 avg60=0.75 avg300=0.10 total=1000000\n")
+	writePressure("memory", "some avg10=0.00 avg60=0.00 avg300=0.00 total=500\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=200\n")
+
+	c := NewPSICollector(procRoot)
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	writePressure("cpu", "some avg10=2.50 avg60=1.25 avg300=0.20 total=1000000000\n") // Longer than the interval
+	writePressure("memory", "some avg10=12.00 avg60=4.00 avg300=1.00 total=1500\nfull avg10=8.00 avg60=2.50 avg300=0.50 total=700\n")
+
+	samples, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	snapshot := &metrics.Snapshot{}
+	for _, s := range samples {
+		s.Apply(snapshot)
+	}
+
+	if names := slices.Sorted(maps.Keys(snapshot.Pressure)); !slices.Equal(names, []string{"cpu", "memory"}) {
+		t.Fatalf("Pressure = %v, want [cpu memory]", names)
+	}
+	cpu := snapshot.Pressure["cpu"]
+	if cpu.Some != 100 || cpu.SomeAvg10 != 2.5 || cpu.SomeAvg60 != 1.25 {
+		t.Errorf("Unexpected cpu pressure: %+v", cpu)
+	}
+	if cpu.Full != -1 || cpu.FullAvg10 != -1 || cpu.FullAvg60 != -1 {
+		t.Errorf("cpu full pressure = %+v, want N/A", cpu)
+	}
+	memory := snapshot.Pressure["memory"]
+	if memory.Some <= 0 || memory.Full <= 0 || memory.Full >= memory.Some || memory.FullAvg10 != 8 || memory.FullAvg60 != 2.5 {
+		t.Errorf("Unexpected memory pressure: %+v", memory)
+	}
+
+	// A decreased total is flagged and drops only that resource for the interval
+	writePressure("memory", "some avg10=0.00 avg60=0.00 avg300=0.00 total=100\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=50\n")
+	samples, err = c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	snapshot = &metrics.Snapshot{}
+	for _, s := range samples {
+		s.Apply(snapshot)
+	}
+	if !slices.Equal(snapshot.CounterResets, []string{"pressure/memory"}) {
+		t.Errorf("CounterResets = %v, want [pressure/memory]", snapshot.CounterResets)
+	}
+	if names := slices.Sorted(maps.Keys(snapshot.Pressure)); !slices.Equal(names, []string{"cpu"}) {
+		t.Errorf("Pressure = %v, want [cpu]", names)
+	}
+
+	writePressure("io", "some avg10=abc avg60=0.00 avg300=0.00 total=0\n")
+	if _, err := c.Collect(context.Background()); err == nil {
+		t.Error("Collect() expected error for a malformed file")
+	}
+
+	if err := NewPSICollector(t.TempDir()).Init(); err == nil {
+		t.Error("Init() expected error without /proc/pressure")
+	}
+}
+
+func TestSystemCollector(t *testing.T) {
+	procRoot := t.TempDir()
+	writeProc := func(stat, loadavg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(procRoot, "stat"), []byte(stat), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(procRoot, "loadavg"), []byte(loadavg), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const cpuLines = "cpu  100 0 50 1000 10 0 5 0 0 0\ncpu0 100 0 50 1000 10 0 5 0 0 0\n"
+
+	writeProc(cpuLines+"intr 1000 10 20 0 0\nctxt 5000\nbtime 1700000000\nprocesses 300\nprocs_running 1\nprocs_blocked 0\nsoftirq 10 1 2\n",
+		"0.10 0.20 0.30 1/200 4567\n")
+
+	c := NewSystemCollector(procRoot)
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	writeProc(cpuLines+"intr 1500 10 20 0 0\nctxt 9000\nbtime 1700000000\nprocesses 310\nprocs_running 6\nprocs_blocked 2\nsoftirq 10 1 2\n",
+		"2.50 1.25 0.75 6/210 4580\n")
+
+	samples, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	snapshot := &metrics.Snapshot{}
+	for _, s := range samples {
+		s.Apply(snapshot)
+	}
+
+	stats := snapshot.System
+	if stats == nil {
+		t.Fatal("System not set")
+	}
+	if stats.Load1 != 2.5 || stats.Load5 != 1.25 || stats.Load15 != 0.75 || stats.ProcsRunning != 6 || stats.ProcsBlocked != 2 {
+		t.Errorf("Unexpected load/run queue: %+v", stats)
+	}
+	// Rates scale with the elapsed time; their ratios match the counter deltas
+	if stats.ContextSwitches <= 0 || math.Abs(stats.ContextSwitches/stats.Interrupts-8) > 1e-9 || math.Abs(stats.Interrupts/stats.Forks-50) > 1e-9 {
+		t.Errorf("Unexpected rates: %+v", stats)
+	}
+
+	// A decreasing counter is flagged instead of reported as a zero rate
+	writeProc(cpuLines+"intr 1600 10 20 0 0\nctxt 100\nbtime 1700000000\nprocesses 320\nprocs_running 1\nprocs_blocked 0\nsoftirq 10 1 2\n",
+		"0.10 0.20 0.30 1/200 4590\n")
+	samples, err = c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	snapshot = &metrics.Snapshot{}
+	for _, s := range samples {
+		s.Apply(snapshot)
+	}
+	if snapshot.System != nil || !slices.Equal(snapshot.CounterResets, []string{"system"}) {
+		t.Errorf("After reset: System = %+v, CounterResets = %v; want nil, [system]", snapshot.System, snapshot.CounterResets)
+	}
+
+	writeProc("cpu  1 2 3 4\nctxt 1\n", "0.00 0.00 0.00 1/1 1\n")
+	if _, err := c.Collect(context.Background()); err == nil {
+		t.Error("Collect() expected error for missing kernel counters")
+	}
+}
+
+func TestNetstatCollector(t *testing.T) {
+	procRoot := t.TempDir()
+	netDir := filepath.Join(procRoot, "net")
+	if err := os.Mkdir(netDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeNet := func(files map[string]string) {
+		t.Helper()
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(netDir, name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	snmp := func(activeOpens, retrans, udpErrors int) string {
+		return "Ip: Forwarding DefaultTTL\nIp: 2 64\n" +
+			"Tcp: RtoAlgorithm RtoMin RtoMax MaxConn ActiveOpens PassiveOpens CurrEstab RetransSegs\n" +
+			fmt.Sprintf("Tcp: 1 200 120000 -1 %d 50 3 %d\n", activeOpens, retrans) +
+			"Udp: InDatagrams NoPorts InErrors OutDatagrams RcvbufErrors\n" +
+			fmt.Sprintf("Udp: 1000 0 %d 900 %d\n", udpErrors, udpErrors/2)
+	}
+	netstat := func(overflows int) string {
+		return "TcpExt: SyncookiesSent ListenOverflows ListenDrops\n" +
+			fmt.Sprintf("TcpExt: 0 %d %d\n", overflows, overflows+1) +
+			"IpExt: InNoRoutes\nIpExt: 0\n"
+	}
+	const tcpHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+	writeNet(map[string]string{"snmp": snmp(100, 10, 4), "netstat": netstat(0), "snmp6": "Udp6InErrors 1\nUdp6RcvbufErrors 0\n"})
+	c := NewNetstatCollector(procRoot) // No tcp tables yet: all states are zero
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	writeNet(map[string]string{
+		"snmp":    snmp(160, 30, 10),
+		"netstat": netstat(5),
+		"snmp6":   "Udp6InErrors 3\nUdp6RcvbufErrors 2\n",
+		"tcp": tcpHeader +
+			"   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1001 1\n" +
+			"   1: 0100007F:1F90 0100007F:C350 01 00000000:00000000 00:00000000 00000000  1000        0 1002 1\n" +
+			"   2: 0100007F:1F90 0100007F:C351 06 00000000:00000000 03:00001770 00000000     0        0 0 3\n",
+		"tcp6": tcpHeader +
+			"   0: 00000000000000000000000001000000:1F90 00000000000000000000000001000000:C352 01 00000000:00000000 00:00000000 00000000  1000        0 1003 1\n" +
+			"   1: 00000000000000000000000001000000:1F90 00000000000000000000000001000000:C353 08 00000000:00000000 00:00000000 00000000  1000        0 1004 1\n",
+	})
+
+	samples, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	snapshot := &metrics.Snapshot{}
+	for _, s := range samples {
+		s.Apply(snapshot)
+	}
+
+	stats := snapshot.Netstat
+	if stats == nil {
+		t.Fatal("Netstat not set")
+	}
+	wantStates := map[string]uint64{"ESTABLISHED": 2, "TIME_WAIT": 1, "CLOSE_WAIT": 1, "LISTEN": 1}
+	for _, state := range metrics.TCPStates {
+		if got, ok := stats.TCPStates[state]; !ok || got != wantStates[state] {
+			t.Errorf("TCPStates[%s] = %d (present %v), want %d", state, got, ok, wantStates[state])
+		}
+	}
+
+	// Rates scale with the elapsed time; their ratios match the counter deltas
+	if stats.ActiveOpens <= 0 || stats.PassiveOpens != 0 || math.Abs(stats.ActiveOpens/stats.RetransSegs-3) > 1e-9 {
+		t.Errorf("Unexpected TCP rates: %+v", stats)
+	}
+	if math.Abs(stats.ListenOverflows-stats.ListenDrops) > 1e-9 || math.Abs(stats.RetransSegs/stats.ListenOverflows-4) > 1e-9 {
+		t.Errorf("Unexpected listen rates: %+v", stats)
+	}
+	// UDP errors include IPv6: (10-4)+(3-1) = 8 and (5-2)+(2-0) = 5
+	if math.Abs(stats.UDPInErrors/stats.UDPRcvbufErrors-8.0/5.0) > 1e-9 {
+		t.Errorf("Unexpected UDP rates: %+v", stats)
+	}
+
+	writeNet(map[string]string{"snmp": "Tcp: ActiveOpens PassiveOpens\nTcp: 1\n"})
+	if _, err := c.Collect(context.Background()); err == nil {
+		t.Error("Collect() expected error for a malformed net/snmp")
+	}
+}
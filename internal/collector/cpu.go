@@ -25,51 +25,141 @@
 package collector
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"time"
 
+	"github.com/phuonguno98/unostat/internal/config"
 	"github.com/phuonguno98/unostat/pkg/metrics"
 	"github.com/shirou/gopsutil/v3/cpu"
 )
 
+func init() {
+	Register("cpu", func(cfg *config.Config) (Collector, error) {
+		if cfg.PerCPU {
+			return NewPerCPUCollector(), nil
+		}
+		return NewCPUCollector(), nil
+	})
+}
+
+// CPUSample holds aggregate CPU utilization, iowait and time breakdown percentages,
+// and per-core metrics when per-CPU collection is enabled.
+type CPUSample struct {
+	Utilization float64
+	IOWait      float64                      // -1 if not available on the platform
+	Breakdown   metrics.CPUBreakdown         // Share of each CPU time component
+	Cores       map[string]metrics.CoreStats // nil unless per-CPU collection is enabled
+}
+
+// Apply stores the CPU sample in the snapshot.
+func (s CPUSample) Apply(snapshot *metrics.Snapshot) {
+	snapshot.CPU = s.Utilization
+	snapshot.CPUWait = s.IOWait
+	snapshot.CPUTimes = s.Breakdown
+	if s.Cores != nil {
+		snapshot.Cores = s.Cores
+	}
+}
+
 // CPUCollector collects CPU utilization and iowait metrics.
 type CPUCollector struct {
 	prevStats metrics.CPUTimeStats
+	prevCores map[string]metrics.CPUTimeStats // Per-core baseline (per-CPU mode only)
+	perCPU    bool
 	firstRun  bool
 }
 
-// NewCPUCollector creates a new CPU collector instance.
+// NewCPUCollector creates a new CPU collector instance that reports the aggregate across all cores.
 func NewCPUCollector() *CPUCollector {
 	return &CPUCollector{
 		firstRun: true,
 	}
 }
 
+// NewPerCPUCollector creates a CPU collector that additionally reports every logical core,
+// exposing a single saturated core that the aggregate hides.
+func NewPerCPUCollector() *CPUCollector {
+	return &CPUCollector{
+		perCPU:   true,
+		firstRun: true,
+	}
+}
+
+// Init takes the baseline CPU time snapshot.
+func (c *CPUCollector) Init() error {
+	currentStats, err := c.getCPUTimeStats()
+	if err != nil {
+		return fmt.Errorf("failed to get CPU stats: %w", err)
+	}
+	c.prevStats = currentStats
+
+	if c.perCPU {
+		cores, err := c.getCoreTimeStats()
+		if err != nil {
+			return fmt.Errorf("failed to get per-CPU stats: %w", err)
+		}
+		c.prevCores = cores
+	}
+
+	c.firstRun = false
+	return nil
+}
+
 // Collect gathers current CPU metrics and calculates utilization.
-// Returns CPU utilization percentage and iowait percentage.
-// IOWait returns -1.0 if not available on the platform.
-func (c *CPUCollector) Collect() (utilization, iowait float64, err error) {
+// Returns a single CPUSample with utilization and iowait percentages,
+// including every core in per-CPU mode. IOWait is -1.0 if not available on the platform.
+// The first call without a prior Init only stores the baseline and returns no samples.
+func (c *CPUCollector) Collect(_ context.Context) ([]Sample, error) {
 	currentStats, err := c.getCPUTimeStats()
 	if err != nil {
-		return 0, -1.0, fmt.Errorf("failed to get CPU stats: %w", err)
+		return nil, fmt.Errorf("failed to get CPU stats: %w", err)
+	}
+
+	var currentCores map[string]metrics.CPUTimeStats
+	if c.perCPU {
+		currentCores, err = c.getCoreTimeStats()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get per-CPU stats: %w", err)
+		}
 	}
 
 	// First run - just store baseline
 	if c.firstRun {
 		c.prevStats = currentStats
+		c.prevCores = currentCores
 		c.firstRun = false
-		return 0, -1.0, nil
+		return nil, nil
 	}
 
 	// Calculate metrics
-	utilization = metrics.CalculateCPUUtilization(&c.prevStats, &currentStats)
-	iowait = metrics.CalculateCPUIOWait(&c.prevStats, &currentStats)
+	sample := CPUSample{
+		Utilization: metrics.CalculateCPUUtilization(&c.prevStats, &currentStats),
+		IOWait:      metrics.CalculateCPUIOWait(&c.prevStats, &currentStats),
+		Breakdown:   metrics.CalculateCPUBreakdown(&c.prevStats, &currentStats),
+	}
+
+	if c.perCPU {
+		sample.Cores = make(map[string]metrics.CoreStats, len(currentCores))
+		for core, current := range currentCores {
+			// A core brought online since the last sample only gets a baseline
+			prev, ok := c.prevCores[core]
+			if !ok {
+				continue
+			}
+			sample.Cores[core] = metrics.CoreStats{
+				Utilization: metrics.CalculateCPUUtilization(&prev, &current),
+				IOWait:      metrics.CalculateCPUIOWait(&prev, &current),
+			}
+		}
+	}
 
 	// Update previous stats
 	c.prevStats = currentStats
+	c.prevCores = currentCores
 
-	return utilization, iowait, nil
+	return []Sample{sample}, nil
 }
 
 // getCPUTimeStats retrieves CPU time statistics from the system.
@@ -88,21 +178,43 @@ func (c *CPUCollector) getCPUTimeStats() (metrics.CPUTimeStats, error) {
 		return stats, fmt.Errorf("no CPU time stats available")
 	}
 
-	t := times[0]
+	return c.toTimeStats(&times[0], stats.Timestamp), nil
+}
+
+// getCoreTimeStats retrieves CPU time statistics of every logical core, keyed by core name.
+func (c *CPUCollector) getCoreTimeStats() (map[string]metrics.CPUTimeStats, error) {
+	now := time.Now()
+
+	times, err := cpu.Times(true)
+	if err != nil {
+		return nil, err
+	}
 
-	stats.User = t.User
-	stats.System = t.System
-	stats.Idle = t.Idle
-	stats.Irq = t.Irq
-	stats.SoftIrq = t.Softirq
-	stats.Steal = t.Steal
-	stats.Guest = t.Guest
-	stats.GuestNice = t.GuestNice
+	if len(times) == 0 {
+		return nil, fmt.Errorf("no per-CPU time stats available")
+	}
 
-	// IOWait handling per platform
-	stats.IOWait = c.getIOWait(&t)
+	cores := make(map[string]metrics.CPUTimeStats, len(times))
+	for i := range times {
+		cores[times[i].CPU] = c.toTimeStats(&times[i], now)
+	}
+	return cores, nil
+}
 
-	return stats, nil
+// toTimeStats converts gopsutil CPU times to CPUTimeStats.
+func (c *CPUCollector) toTimeStats(t *cpu.TimesStat, ts time.Time) metrics.CPUTimeStats {
+	return metrics.CPUTimeStats{
+		User:      t.User,
+		System:    t.System,
+		Idle:      t.Idle,
+		IOWait:    c.getIOWait(t), // IOWait handling per platform
+		Irq:       t.Irq,
+		SoftIrq:   t.Softirq,
+		Steal:     t.Steal,
+		Guest:     t.Guest,
+		GuestNice: t.GuestNice,
+		Timestamp: ts,
+	}
 }
 
 // getIOWait extracts iowait value with platform-specific handling.
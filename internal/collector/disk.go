@@ -25,22 +25,57 @@
 package collector
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"time"
 
+	"github.com/phuonguno98/unostat/internal/config"
 	"github.com/phuonguno98/unostat/pkg/metrics"
 	"github.com/shirou/gopsutil/v3/disk"
 )
 
+func init() {
+	Register("disk", func(cfg *config.Config) (Collector, error) {
+		c, err := NewDiskCollector(cfg.IncludeDisks, cfg.ExcludeDisks)
+		if err != nil {
+			return nil, err
+		}
+		c.counter32 = cfg.CounterWrap32
+		c.physicalOnly = cfg.PhysicalOnly
+		c.sysRoot = cfg.Host.SysRoot()
+		return c, nil
+	})
+}
+
+// DiskSample holds the metrics of a single device.
+type DiskSample struct {
+	Device string
+	Stats  metrics.DiskStats
+}
+
+// Apply stores the device metrics in the snapshot.
+func (s DiskSample) Apply(snapshot *metrics.Snapshot) {
+	if snapshot.Disks == nil {
+		snapshot.Disks = make(map[string]metrics.DiskStats)
+	}
+	snapshot.Disks[s.Device] = s.Stats
+}
+
 // DiskCollector collects disk I/O metrics.
 type DiskCollector struct {
-	prevStats      map[string]metrics.DiskIOStats
-	includeDevices []string // Devices to monitor (empty = all)
-	excludeDevices []string // Devices to exclude
-	firstRun       bool
+	prevStats    map[string]metrics.DiskIOStats
+	filter       nameFilter // Include/exclude device patterns
+	physicalOnly bool       // Skip virtual devices (loop, ram, zram, device-mapper...)
+	sysRoot      string     // Mountpoint of sysfs, to tell virtual devices apart
+	counter32    bool       // Counters wrap around at 2^32
+	resets       []string   // Devices whose counters were reset during the last collection
+	firstRun     bool
 }
 
+// virtualDiskPrefixes lists the names of virtual block devices, used where sysfs is not available.
+var virtualDiskPrefixes = []string{"loop", "ram", "zram", "dm-", "md", "nbd"}
+
 // normalizeDeviceName strips /dev/ prefix from device names for consistent comparison.
 // This allows users to specify devices as shown in list-devices (/dev/sdd)
 // while internally matching against disk.IOCounters() format (sdd).
@@ -62,27 +97,59 @@ func normalizeDeviceList(devices []string) []string {
 }
 
 // NewDiskCollector creates a new disk collector instance.
-// includeDevices: list of device names to monitor (empty = all available)
-// excludeDevices: list of device names to exclude
-// Device names can be specified with or without /dev/ prefix (e.g., "sdd" or "/dev/sdd")
-func NewDiskCollector(includeDevices, excludeDevices []string) *DiskCollector {
-	return &DiskCollector{
-		prevStats:      make(map[string]metrics.DiskIOStats),
-		includeDevices: normalizeDeviceList(includeDevices),
-		excludeDevices: normalizeDeviceList(excludeDevices),
-		firstRun:       true,
+// includeDevices: list of device patterns to monitor (empty = all available)
+// excludeDevices: list of device patterns to exclude
+// Patterns are exact names, globs (e.g., "sd*") or regular expressions prefixed with "re:",
+// with or without /dev/ prefix (e.g., "sdd" or "/dev/sdd").
+// Returns an error if a pattern is invalid.
+func NewDiskCollector(includeDevices, excludeDevices []string) (*DiskCollector, error) {
+	filter, err := newNameFilter(normalizeDeviceList(includeDevices), normalizeDeviceList(excludeDevices))
+	if err != nil {
+		return nil, fmt.Errorf("invalid disk filter: %w", err)
 	}
+	return &DiskCollector{
+		prevStats: make(map[string]metrics.DiskIOStats),
+		filter:    filter,
+		sysRoot:   config.DefaultSysRoot,
+		firstRun:  true,
+	}, nil
+}
+
+// Init takes the baseline disk I/O counters.
+func (d *DiskCollector) Init() error {
+	_, err := d.collectStats()
+	return err
 }
 
 // Collect gathers current disk I/O metrics.
-// Returns map of device names to DiskStats.
-func (d *DiskCollector) Collect() (map[string]metrics.DiskStats, error) {
+// Returns one DiskSample per monitored device.
+// The first call without a prior Init only stores the baseline and returns no samples.
+func (d *DiskCollector) Collect(_ context.Context) ([]Sample, error) {
+	stats, err := d.collectStats()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]Sample, 0, len(stats)+len(d.resets))
+	for name, st := range stats {
+		samples = append(samples, DiskSample{Device: name, Stats: st})
+	}
+	for _, name := range d.resets {
+		samples = append(samples, CounterResetSample{Source: "disk/" + name})
+	}
+	return samples, nil
+}
+
+// collectStats reads the current disk I/O counters and computes metrics against the previous ones.
+// Returns map of device names to DiskStats, or nil on the baseline run.
+func (d *DiskCollector) collectStats() (map[string]metrics.DiskStats, error) {
 	ioCounters, err := disk.IOCounters()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get disk I/O counters: %w", err)
 	}
 
 	result := make(map[string]metrics.DiskStats)
+	d.resets = d.resets[:0]
 	now := time.Now()
 
 	for deviceName := range ioCounters {
@@ -101,9 +168,13 @@ func (d *DiskCollector) Collect() (map[string]metrics.DiskStats, error) {
 		currentStats := metrics.DiskIOStats{
 			ReadCount:  counter.ReadCount,
 			WriteCount: counter.WriteCount,
+			ReadBytes:  counter.ReadBytes,
+			WriteBytes: counter.WriteBytes,
 			ReadTime:   counter.ReadTime,
 			WriteTime:  counter.WriteTime,
 			IOTime:     d.getIOTime(&counter),
+			WeightedIO: counter.WeightedIO,
+			Counter32:  d.counter32,
 			Timestamp:  now,
 		}
 
@@ -120,15 +191,34 @@ func (d *DiskCollector) Collect() (map[string]metrics.DiskStats, error) {
 			continue
 		}
 
+		// A reset device (e.g. re-attached or driver reloaded) has no valid delta;
+		// re-baseline it and report it as N/A for this interval
+		if metrics.DiskCountersReset(prevStats, currentStats) {
+			d.prevStats[deviceName] = currentStats
+			d.resets = append(d.resets, deviceName)
+			continue
+		}
+
 		// Calculate metrics
 		utilization := metrics.CalculateDiskUtilization(prevStats, currentStats)
 		await := metrics.CalculateDiskAwait(prevStats, currentStats)
 		iops := metrics.CalculateDiskIOPS(prevStats, currentStats)
+		readIOPS, writeIOPS := metrics.CalculateDiskReadWriteIOPS(prevStats, currentStats)
+		readThroughput, writeThroughput := metrics.CalculateDiskReadWriteThroughput(prevStats, currentStats)
+		readAwait, writeAwait := metrics.CalculateDiskReadWriteAwait(prevStats, currentStats)
 
 		result[deviceName] = metrics.DiskStats{
-			Utilization: utilization,
-			Await:       await,
-			IOPS:        iops,
+			Utilization:     utilization,
+			Await:           await,
+			IOPS:            iops,
+			ReadIOPS:        readIOPS,
+			WriteIOPS:       writeIOPS,
+			ReadThroughput:  readThroughput,
+			WriteThroughput: writeThroughput,
+			ReadAwait:       readAwait,
+			WriteAwait:      writeAwait,
+			AvgRequestSize:  metrics.CalculateDiskAvgRequestSize(prevStats, currentStats),
+			QueueDepth:      metrics.CalculateDiskQueueDepth(prevStats, currentStats),
 		}
 
 		// Update previous stats
@@ -154,30 +244,13 @@ func (d *DiskCollector) getIOTime(counter *disk.IOCountersStat) uint64 {
 	return counter.IoTime
 }
 
-// shouldMonitor checks if a device should be monitored based on include/exclude filters.
+// shouldMonitor checks if a device should be monitored based on include/exclude filters
+// and, in physical-only mode, on whether it is virtual.
 func (d *DiskCollector) shouldMonitor(deviceName string) bool {
-	// Check exclude list first
-	if len(d.excludeDevices) > 0 {
-		for _, excluded := range d.excludeDevices {
-			if excluded == deviceName {
-				return false
-			}
-		}
-	}
-
-	// If include list is empty, monitor all (except excluded)
-	if len(d.includeDevices) == 0 {
-		return true
+	if d.physicalOnly && isVirtualDevice(d.sysRoot, "block", deviceName, virtualDiskPrefixes) {
+		return false
 	}
-
-	// Check include list
-	for _, included := range d.includeDevices {
-		if included == deviceName {
-			return true
-		}
-	}
-
-	return false
+	return d.filter.allows(deviceName)
 }
 
 // Name returns the collector name for logging purposes.
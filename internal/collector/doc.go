@@ -24,6 +24,7 @@
 
 // Package collector handles the retrieval of system metrics.
 //
-// It provides a Manager to coordinate sampling of CPU, Memory, Disk, and Network
-// stats at configured intervals, sending Snapshots to the processing pipeline.
+// It provides a Manager to coordinate sampling of the registered collectors
+// (CPU, Memory, Disk, Network, ...) at configured intervals, sending Snapshots
+// to the processing pipeline.
 package collector
@@ -0,0 +1,174 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+func init() {
+	Register("filesystem", func(cfg *config.Config) (Collector, error) {
+		c := NewFilesystemCollector(cfg.IncludeMountpoints, cfg.ExcludeMountpoints,
+			cfg.IncludeFstypes, cfg.ExcludeFstypes)
+		c.rootFS = cfg.Host.Root
+		return c, nil
+	})
+}
+
+// Dependency injection points for testing
+var (
+	fsPartitions = disk.PartitionsWithContext
+	fsUsage      = disk.UsageWithContext
+)
+
+// pseudoFstypes lists virtual filesystems that carry no user data and are never sampled,
+// unless explicitly requested with an include filter.
+var pseudoFstypes = []string{
+	"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs",
+	"devpts", "devtmpfs", "efivarfs", "fusectl", "hugetlbfs", "mqueue", "nsfs",
+	"proc", "pstore", "rpc_pipefs", "securityfs", "squashfs", "sysfs", "tracefs",
+}
+
+// FilesystemSample holds the capacity metrics of a single mountpoint.
+type FilesystemSample struct {
+	Mountpoint string
+	Stats      metrics.FilesystemStats
+}
+
+// Apply stores the filesystem metrics in the snapshot.
+func (s FilesystemSample) Apply(snapshot *metrics.Snapshot) {
+	if snapshot.Filesystems == nil {
+		snapshot.Filesystems = make(map[string]metrics.FilesystemStats)
+	}
+	snapshot.Filesystems[s.Mountpoint] = s.Stats
+}
+
+// FilesystemCollector collects used/free space and inode usage of mounted filesystems.
+// Unlike the I/O collectors it reports absolute values, so every collection produces samples.
+type FilesystemCollector struct {
+	includeMountpoints []string // Mountpoints to monitor (empty = all)
+	excludeMountpoints []string // Mountpoints to exclude
+	includeFstypes     []string // Filesystem types to monitor (empty = all non-pseudo)
+	excludeFstypes     []string // Filesystem types to exclude
+	rootFS             string   // Mountpoint of the host root filesystem to resolve mountpoints under (empty = /)
+}
+
+// NewFilesystemCollector creates a new filesystem collector instance.
+// includeMountpoints/excludeMountpoints filter by mountpoint (e.g., "/", "/var/log"),
+// includeFstypes/excludeFstypes by filesystem type (e.g., "ext4", "xfs").
+// Pseudo filesystems are skipped unless their type is explicitly included.
+func NewFilesystemCollector(includeMountpoints, excludeMountpoints, includeFstypes, excludeFstypes []string) *FilesystemCollector {
+	return &FilesystemCollector{
+		includeMountpoints: includeMountpoints,
+		excludeMountpoints: excludeMountpoints,
+		includeFstypes:     includeFstypes,
+		excludeFstypes:     excludeFstypes,
+	}
+}
+
+// Init checks that the mounted filesystems can be enumerated.
+func (f *FilesystemCollector) Init() error {
+	if _, err := fsPartitions(context.Background(), true); err != nil {
+		return fmt.Errorf("failed to get disk partitions: %w", err)
+	}
+	return nil
+}
+
+// Collect gathers the current usage of every monitored filesystem.
+// Returns one FilesystemSample per mountpoint. Mountpoints whose usage cannot be read
+// (e.g. a stale network mount) are skipped.
+func (f *FilesystemCollector) Collect(ctx context.Context) ([]Sample, error) {
+	partitions, err := fsPartitions(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk partitions: %w", err)
+	}
+
+	// A mountpoint can be stacked several times; only the last mount is visible
+	visible := make(map[string]int, len(partitions))
+	for i, partition := range partitions {
+		visible[partition.Mountpoint] = i
+	}
+
+	samples := make([]Sample, 0, len(visible))
+	for i, partition := range partitions {
+		if visible[partition.Mountpoint] != i || !f.shouldMonitor(partition.Mountpoint, partition.Fstype) {
+			continue
+		}
+
+		// Mountpoints are reported as seen by the host
+		usage, err := fsUsage(ctx, filepath.Join(f.rootFS, partition.Mountpoint))
+		if err != nil || usage.Total == 0 {
+			continue
+		}
+
+		samples = append(samples, FilesystemSample{
+			Mountpoint: partition.Mountpoint,
+			Stats: metrics.FilesystemStats{
+				Device:            partition.Device,
+				Fstype:            partition.Fstype,
+				Total:             usage.Total,
+				Used:              usage.Used,
+				Free:              usage.Free,
+				UsedPercent:       usage.UsedPercent,
+				InodesTotal:       usage.InodesTotal,
+				InodesUsed:        usage.InodesUsed,
+				InodesFree:        usage.InodesFree,
+				InodesUsedPercent: usage.InodesUsedPercent,
+			},
+		})
+	}
+
+	return samples, nil
+}
+
+// shouldMonitor checks if a filesystem should be monitored based on include/exclude filters.
+// Exclude filters take priority; pseudo filesystems need their type to be explicitly included.
+func (f *FilesystemCollector) shouldMonitor(mountpoint, fstype string) bool {
+	if slices.Contains(f.excludeMountpoints, mountpoint) || slices.Contains(f.excludeFstypes, fstype) {
+		return false
+	}
+
+	if len(f.includeFstypes) > 0 {
+		if !slices.Contains(f.includeFstypes, fstype) {
+			return false
+		}
+	} else if slices.Contains(pseudoFstypes, fstype) {
+		return false
+	}
+
+	return len(f.includeMountpoints) == 0 || slices.Contains(f.includeMountpoints, mountpoint)
+}
+
+// Name returns the collector name for logging purposes.
+func (f *FilesystemCollector) Name() string {
+	return "Filesystem"
+}
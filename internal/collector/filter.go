@@ -0,0 +1,139 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// regexPrefix marks a filter pattern as a regular expression.
+const regexPrefix = "re:"
+
+// namePattern matches device or interface names. A pattern is a regular expression when
+// prefixed with "re:" (e.g., "re:^nvme[0-9]+n1$"), a glob when it contains *, ? or [
+// (e.g., "veth*"), and an exact name otherwise.
+type namePattern struct {
+	exact string
+	glob  string
+	re    *regexp.Regexp
+}
+
+// compileNamePattern parses a filter pattern.
+func compileNamePattern(pattern string) (namePattern, error) {
+	if expr, ok := strings.CutPrefix(pattern, regexPrefix); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return namePattern{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		return namePattern{re: re}, nil
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return namePattern{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		return namePattern{glob: pattern}, nil
+	}
+	return namePattern{exact: pattern}, nil
+}
+
+// match reports whether name matches the pattern.
+func (p namePattern) match(name string) bool {
+	switch {
+	case p.re != nil:
+		return p.re.MatchString(name)
+	case p.glob != "":
+		matched, _ := path.Match(p.glob, name) // Validated when compiled
+		return matched
+	default:
+		return p.exact == name
+	}
+}
+
+// nameFilter selects devices or interfaces by include/exclude patterns.
+type nameFilter struct {
+	include []namePattern // Names to monitor (empty = all)
+	exclude []namePattern // Names to exclude
+}
+
+// newNameFilter compiles the include and exclude patterns.
+func newNameFilter(include, exclude []string) (nameFilter, error) {
+	var f nameFilter
+	for _, list := range []struct {
+		patterns []string
+		compiled *[]namePattern
+	}{{include, &f.include}, {exclude, &f.exclude}} {
+		for _, pattern := range list.patterns {
+			p, err := compileNamePattern(pattern)
+			if err != nil {
+				return nameFilter{}, err
+			}
+			*list.compiled = append(*list.compiled, p)
+		}
+	}
+	return f, nil
+}
+
+// allows checks if a name passes the filter. Exclude patterns take priority.
+func (f nameFilter) allows(name string) bool {
+	for _, p := range f.exclude {
+		if p.match(name) {
+			return false
+		}
+	}
+
+	// If include list is empty, monitor all (except excluded)
+	if len(f.include) == 0 {
+		return true
+	}
+
+	for _, p := range f.include {
+		if p.match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isVirtualDevice checks if a block device or network interface is virtual, i.e. its sysfs
+// entry (e.g. /sys/class/net/veth1a2b) links below /sys/devices/virtual. Where the entry is
+// not available (no sysfs, or another network namespace), common names of virtual devices
+// are recognized instead.
+func isVirtualDevice(sysRoot, class, name string, virtualPrefixes []string) bool {
+	if target, err := os.Readlink(filepath.Join(sysRoot, "class", class, name)); err == nil {
+		return strings.Contains(filepath.ToSlash(target), "/devices/virtual/")
+	}
+
+	for _, prefix := range virtualPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
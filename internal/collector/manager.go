@@ -28,6 +28,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 
@@ -40,40 +41,52 @@ var startUpDelay = 1 * time.Second
 // Manager orchestrates all metric collectors.
 type Manager struct {
 	config      *config.Config
-	cpu         *CPUCollector
-	memory      *MemoryCollector
-	disk        *DiskCollector
-	network     *NetworkCollector
+	collectors  []Collector
 	metricsChan chan<- *metrics.Snapshot
 	ticker      *time.Ticker
 	logger      *slog.Logger
 }
 
 // NewManager creates a new collector manager instance.
-func NewManager(cfg *config.Config, metricsChan chan<- *metrics.Snapshot, logger *slog.Logger) *Manager {
+// The collectors are instantiated from the registry according to the configuration.
+func NewManager(cfg *config.Config, metricsChan chan<- *metrics.Snapshot, logger *slog.Logger) (*Manager, error) {
+	collectors, err := Build(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewManagerWithCollectors(cfg, collectors, metricsChan, logger), nil
+}
+
+// NewManagerWithCollectors creates a collector manager that drives the given collectors.
+func NewManagerWithCollectors(cfg *config.Config, collectors []Collector, metricsChan chan<- *metrics.Snapshot, logger *slog.Logger) *Manager {
 	return &Manager{
 		config:      cfg,
-		cpu:         NewCPUCollector(),
-		memory:      NewMemoryCollector(),
-		disk:        NewDiskCollector(cfg.IncludeDisks, cfg.ExcludeDisks),
-		network:     NewNetworkCollector(cfg.IncludeNetworks, cfg.ExcludeNetworks),
+		collectors:  collectors,
 		metricsChan: metricsChan,
 		logger:      logger,
 	}
 }
 
+// Collectors returns the names of the active collectors.
+func (m *Manager) Collectors() []string {
+	names := make([]string, 0, len(m.collectors))
+	for _, c := range m.collectors {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
 // Start begins the collection loop.
-// It performs an initial baseline collection, then collects metrics at the configured interval.
+// It initializes every collector (baseline collection), then collects metrics at the configured interval.
 func (m *Manager) Start(ctx context.Context) error {
 	m.logger.Info("Starting collector manager",
 		"interval", m.config.SamplingInterval,
+		"collectors", m.Collectors(),
 	)
 
 	// Perform baseline collection
 	m.logger.Info("Performing baseline collection...")
-	if err := m.collectOnce(); err != nil {
-		m.logger.Warn("Baseline collection had errors", "error", err)
-	}
+	m.initCollectors()
 
 	// Wait a bit before starting regular collection
 	select {
@@ -95,105 +108,84 @@ func (m *Manager) Start(ctx context.Context) error {
 			return nil
 
 		case <-m.ticker.C:
-			if err := m.collectOnce(); err != nil {
+			if err := m.collectOnce(ctx); err != nil {
 				m.logger.Error("Collection failed", "error", err)
 			}
 		}
 	}
 }
 
+// initCollectors calls Init on every collector.
+// Collectors that fail to initialize are logged and dropped from the collection loop.
+func (m *Manager) initCollectors() {
+	active := m.collectors[:0]
+	for _, c := range m.collectors {
+		if err := c.Init(); err != nil {
+			m.logger.Warn("Failed to initialize collector, disabling it", "collector", c.Name(), "error", err)
+			continue
+		}
+		active = append(active, c)
+	}
+	m.collectors = active
+}
+
 // collectOnce performs a single collection cycle concurrently.
 // It gathers metrics from all collectors in parallel to minimize total collection time.
-func (m *Manager) collectOnce() error {
+func (m *Manager) collectOnce(ctx context.Context) error {
 	snapshot := &metrics.Snapshot{
-		Timestamp: time.Now(),
-		Disks:     make(map[string]metrics.DiskStats),
-		Networks:  make(map[string]metrics.NetStats),
+		Timestamp:   time.Now(),
+		CPUWait:     -1.0, // N/A unless the CPU collector reports it
+		Disks:       make(map[string]metrics.DiskStats),
+		Networks:    make(map[string]metrics.NetStats),
+		Filesystems: make(map[string]metrics.FilesystemStats),
 	}
 
 	var (
-		wg sync.WaitGroup
-		mu sync.Mutex // Protects snapshot updates
+		wg       sync.WaitGroup
+		mu       sync.Mutex // Protects snapshot updates
+		nSamples int
 	)
 
-	// We have 4 collectors to run in parallel
-	wg.Add(4)
+	wg.Add(len(m.collectors))
+	for _, c := range m.collectors {
+		go func(c Collector) {
+			defer wg.Done()
+			samples, err := c.Collect(ctx)
+			if err != nil {
+				m.logger.Warn("Failed to collect metrics", "collector", c.Name(), "error", err)
+				return
+			}
 
-	// Collect CPU metrics
-	go func() {
-		defer wg.Done()
-		cpuUtil, cpuWait, err := m.cpu.Collect()
-		if err != nil {
-			m.logger.Warn("Failed to collect CPU metrics", "error", err)
-		} else {
 			mu.Lock()
-			snapshot.CPU = cpuUtil
-			snapshot.CPUWait = cpuWait
-			mu.Unlock()
-		}
-	}()
-
-	// Collect Memory metrics
-	go func() {
-		defer wg.Done()
-		memUtil, err := m.memory.Collect()
-		if err != nil {
-			m.logger.Warn("Failed to collect memory metrics", "error", err)
-		} else {
-			mu.Lock()
-			snapshot.Memory = memUtil
-			mu.Unlock()
-		}
-	}()
-
-	// Collect Disk metrics
-	go func() {
-		defer wg.Done()
-		diskStats, err := m.disk.Collect()
-		if err != nil {
-			m.logger.Warn("Failed to collect disk metrics", "error", err)
-		} else if diskStats != nil {
-			mu.Lock()
-			snapshot.Disks = diskStats
-			mu.Unlock()
-		}
-	}()
-
-	// Collect Network metrics
-	go func() {
-		defer wg.Done()
-		netStats, err := m.network.Collect()
-		if err != nil {
-			m.logger.Warn("Failed to collect network metrics", "error", err)
-		} else if netStats != nil {
-			mu.Lock()
-			snapshot.Networks = netStats
+			for _, sample := range samples {
+				sample.Apply(snapshot)
+			}
+			nSamples += len(samples)
 			mu.Unlock()
-		}
-	}()
+		}(c)
+	}
 
 	// Wait for all collectors to finish
 	wg.Wait()
 
-	// Check if this is baseline collection (or no useful data)
-	mu.Lock()
-	disksEmpty := len(snapshot.Disks) == 0
-	netsEmpty := len(snapshot.Networks) == 0
-	disksCount := len(snapshot.Disks)
-	netsCount := len(snapshot.Networks)
-	mu.Unlock()
-
 	m.logger.Debug("Collection completed",
 		"cpu", snapshot.CPU,
 		"memory", snapshot.Memory,
-		"disks_count", disksCount,
-		"networks_count", netsCount,
+		"disks_count", len(snapshot.Disks),
+		"networks_count", len(snapshot.Networks),
+		"filesystems_count", len(snapshot.Filesystems),
+		"samples", nSamples,
 	)
 
-	// Only skip if BOTH disks AND networks are empty (baseline collection)
-	// This allows metrics to be sent even when filtering results in only disk or only network data
-	if disksEmpty && netsEmpty {
-		m.logger.Debug("Baseline collection completed - both disks and networks empty")
+	if len(snapshot.CounterResets) > 0 {
+		// Collectors run concurrently; keep the order stable for exporters
+		sort.Strings(snapshot.CounterResets)
+		m.logger.Warn("Counter reset detected, metrics re-baselined", "sources", snapshot.CounterResets)
+	}
+
+	// Nothing was collected (every collector failed or is still on its baseline run)
+	if nSamples == 0 {
+		m.logger.Debug("No samples collected, skipping snapshot")
 		return nil
 	}
 
@@ -25,35 +25,127 @@
 package collector
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
 	"github.com/shirou/gopsutil/v3/mem"
 )
 
-// MemoryCollector collects memory utilization metrics.
-type MemoryCollector struct{}
+func init() {
+	Register("memory", func(_ *config.Config) (Collector, error) {
+		return NewMemoryCollector(), nil
+	})
+}
+
+// swapPageSize converts gopsutil swap counters back to pages.
+// gopsutil reports pswpin/pswpout from /proc/vmstat multiplied by a fixed 4 KiB.
+const swapPageSize = 4 * 1024
+
+// Dependency injection point for testing
+var readSwapMemory = mem.SwapMemory
+
+// MemorySample holds the memory utilization percentage and the memory breakdown.
+type MemorySample struct {
+	Utilization float64
+	Detail      metrics.MemoryStats
+}
+
+// Apply stores the memory sample in the snapshot.
+func (s MemorySample) Apply(snapshot *metrics.Snapshot) {
+	snapshot.Memory = s.Utilization
+	snapshot.MemDetail = s.Detail
+}
+
+// MemoryCollector collects memory utilization, breakdown and swap metrics.
+// Swap metrics are optional: where they cannot be read, they are reported as zero.
+type MemoryCollector struct {
+	prevSwap    metrics.SwapIOStats
+	swapMissing bool // Swap stats could not be read at the last attempt
+}
 
 // NewMemoryCollector creates a new memory collector instance.
 func NewMemoryCollector() *MemoryCollector {
 	return &MemoryCollector{}
 }
 
+// Init takes the baseline of the swap activity counters. A missing swap is not an error.
+func (m *MemoryCollector) Init() error {
+	m.readSwap()
+	return nil
+}
+
+// readSwap reads the swap stats and stores them as the new baseline.
+// It returns nil stats, and clears the baseline, when swap is not available.
+func (m *MemoryCollector) readSwap() *mem.SwapMemoryStat {
+	swapStat, err := readSwapMemory()
+	if err != nil {
+		if !m.swapMissing {
+			slog.Debug("Swap stats not available, reporting zero swap", "error", err)
+		}
+		m.swapMissing = true
+		m.prevSwap = metrics.SwapIOStats{}
+		return nil
+	}
+	m.swapMissing = false
+	m.prevSwap = toSwapIOStats(swapStat, time.Now())
+	return swapStat
+}
+
 // Collect gathers current memory metrics.
-// Returns memory utilization percentage.
-func (m *MemoryCollector) Collect() (float64, error) {
+// Returns a single MemorySample with the utilization percentage and the memory breakdown.
+// Swap rates are zero until a baseline exists, i.e. on the first call without a prior Init.
+func (m *MemoryCollector) Collect(_ context.Context) ([]Sample, error) {
 	vmStat, err := mem.VirtualMemory()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get memory stats: %w", err)
+		return nil, fmt.Errorf("failed to get memory stats: %w", err)
 	}
 
 	// Calculate utilization: (Used / Total) × 100
 	if vmStat.Total == 0 {
-		return 0, fmt.Errorf("total memory is zero")
+		return nil, fmt.Errorf("total memory is zero")
 	}
 
-	utilization := (float64(vmStat.Used) / float64(vmStat.Total)) * 100.0
+	prevSwap := m.prevSwap
+	swapStat := m.readSwap()
+
+	sample := MemorySample{
+		Utilization: (float64(vmStat.Used) / float64(vmStat.Total)) * 100.0,
+		Detail: metrics.MemoryStats{
+			Available: vmStat.Available,
+			Cached:    vmStat.Cached,
+			Buffers:   vmStat.Buffers,
+			Dirty:     vmStat.Dirty,
+			WriteBack: vmStat.WriteBack,
+			Slab:      vmStat.Slab,
+		},
+	}
 
-	return utilization, nil
+	var reset []Sample
+	if swapStat != nil {
+		sample.Detail.SwapUsed = swapStat.Used
+		sample.Detail.SwapTotal = swapStat.Total
+		// A reset has no valid delta: the new counters are the baseline and the rates stay zero
+		if metrics.SwapCountersReset(prevSwap, m.prevSwap) {
+			reset = append(reset, CounterResetSample{Source: "swap"})
+		} else {
+			sample.Detail.SwapIn, sample.Detail.SwapOut = metrics.CalculateSwapRates(prevSwap, m.prevSwap)
+		}
+	}
+
+	return append([]Sample{sample}, reset...), nil
+}
+
+// toSwapIOStats converts gopsutil swap counters to SwapIOStats.
+func toSwapIOStats(s *mem.SwapMemoryStat, ts time.Time) metrics.SwapIOStats {
+	return metrics.SwapIOStats{
+		PagesIn:   s.Sin / swapPageSize,
+		PagesOut:  s.Sout / swapPageSize,
+		Timestamp: ts,
+	}
 }
 
 // Name returns the collector name for logging purposes.
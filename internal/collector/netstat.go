@@ -0,0 +1,243 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package collector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func init() {
+	Register("netstat", func(cfg *config.Config) (Collector, error) {
+		if runtime.GOOS != "linux" {
+			return nil, nil // Linux only
+		}
+		return NewNetstatCollector(cfg.Host.ProcRoot()), nil
+	})
+}
+
+// NetstatSample holds the TCP/UDP protocol statistics of an interval.
+type NetstatSample struct {
+	Stats metrics.NetstatStats
+}
+
+// Apply stores the protocol statistics in the snapshot.
+func (s NetstatSample) Apply(snapshot *metrics.Snapshot) {
+	stats := s.Stats
+	snapshot.Netstat = &stats
+}
+
+// NetstatCollector collects TCP connection counts by state and the TCP/UDP error and
+// connection rates that reveal retransmits and connection exhaustion under load.
+type NetstatCollector struct {
+	procRoot string // Mountpoint of procfs
+	prev     metrics.NetstatCounters
+}
+
+// NewNetstatCollector creates a new protocol statistics collector reading procfs mounted at procRoot.
+func NewNetstatCollector(procRoot string) *NetstatCollector {
+	return &NetstatCollector{procRoot: procRoot}
+}
+
+// Init takes the baseline protocol counters.
+func (n *NetstatCollector) Init() error {
+	_, err := n.Collect(context.Background())
+	return err
+}
+
+// Collect gathers the current protocol counters and connection states.
+// Returns a single NetstatSample.
+// The first call without a prior Init only stores the baseline and returns no samples.
+func (n *NetstatCollector) Collect(_ context.Context) ([]Sample, error) {
+	counters, err := n.readCounters()
+	if err != nil {
+		return nil, err
+	}
+	counters.Timestamp = time.Now()
+
+	states := make(map[string]uint64, len(metrics.TCPStates))
+	for _, state := range metrics.TCPStates {
+		states[state] = 0
+	}
+	for _, table := range []string{"tcp", "tcp6"} {
+		if err := countTCPStates(filepath.Join(n.procRoot, "net", table), states); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read %s connections: %w", table, err)
+		}
+	}
+
+	prev := n.prev
+	n.prev = counters
+	if prev.Timestamp.IsZero() {
+		return nil, nil // Baseline only
+	}
+	if metrics.NetstatCountersReset(prev, counters) {
+		return []Sample{CounterResetSample{Source: "netstat"}}, nil // N/A for this interval
+	}
+
+	stats := metrics.CalculateNetstatRates(prev, counters)
+	stats.TCPStates = states
+	return []Sample{NetstatSample{Stats: stats}}, nil
+}
+
+// readCounters reads the cumulative TCP and UDP counters from /proc/net/snmp and /proc/net/netstat,
+// adding the IPv6 UDP errors of /proc/net/snmp6 when IPv6 is enabled.
+func (n *NetstatCollector) readCounters() (metrics.NetstatCounters, error) {
+	var counters metrics.NetstatCounters
+
+	snmp, err := readProtoCounters(filepath.Join(n.procRoot, "net", "snmp"))
+	if err != nil {
+		return counters, fmt.Errorf("failed to read SNMP counters: %w", err)
+	}
+	tcp, udp := snmp["Tcp"], snmp["Udp"]
+	if tcp == nil || udp == nil {
+		return counters, errors.New("missing Tcp or Udp counters in net/snmp")
+	}
+	counters.ActiveOpens = tcp["ActiveOpens"]
+	counters.PassiveOpens = tcp["PassiveOpens"]
+	counters.RetransSegs = tcp["RetransSegs"]
+	counters.UDPInErrors = udp["InErrors"]
+	counters.UDPRcvbufErrors = udp["RcvbufErrors"]
+
+	netstat, err := readProtoCounters(filepath.Join(n.procRoot, "net", "netstat"))
+	if err != nil {
+		return counters, fmt.Errorf("failed to read extended TCP counters: %w", err)
+	}
+	counters.ListenOverflows = netstat["TcpExt"]["ListenOverflows"]
+	counters.ListenDrops = netstat["TcpExt"]["ListenDrops"]
+
+	snmp6, err := readSNMP6Counters(filepath.Join(n.procRoot, "net", "snmp6"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return counters, fmt.Errorf("failed to read IPv6 SNMP counters: %w", err)
+	}
+	counters.UDPInErrors += snmp6["Udp6InErrors"]
+	counters.UDPRcvbufErrors += snmp6["Udp6RcvbufErrors"]
+
+	return counters, nil
+}
+
+// Name returns the collector name for logging purposes.
+func (n *NetstatCollector) Name() string {
+	return "Netstat"
+}
+
+// readProtoCounters parses a /proc/net/snmp style file, where each protocol has a line
+// of field names followed by a line of values:
+//
+//	Tcp: RtoAlgorithm RtoMin RtoMax MaxConn ActiveOpens ...
+//	Tcp: 1 200 120000 -1 83 ...
+//
+// Returns the counters keyed by protocol and field. Negative values (e.g. MaxConn) are skipped.
+func readProtoCounters(path string) (map[string]map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	protos := make(map[string]map[string]uint64)
+	var names []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // TcpExt has hundreds of fields
+	for scanner.Scan() {
+		proto, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if names == nil {
+			names = fields
+			continue
+		}
+
+		if len(fields) != len(names) {
+			return nil, fmt.Errorf("%s: %d values for %d fields", proto, len(fields), len(names))
+		}
+		values := make(map[string]uint64, len(names))
+		for i, name := range names {
+			if v, err := strconv.ParseUint(fields[i], 10, 64); err == nil {
+				values[name] = v
+			}
+		}
+		protos[proto] = values
+		names = nil
+	}
+	return protos, scanner.Err()
+}
+
+// readSNMP6Counters parses /proc/net/snmp6, which lists one "name value" pair per line.
+func readSNMP6Counters(path string) (map[string]uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]uint64)
+	for line := range strings.Lines(string(data)) {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			counters[fields[0]] = v
+		}
+	}
+	return counters, nil
+}
+
+// countTCPStates adds the connections of a /proc/net/tcp table to states, keyed by state name.
+// The state is the hexadecimal "st" column.
+func countTCPStates(path string, states map[string]uint64) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // Skip header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		st, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil || st == 0 || int(st) > len(metrics.TCPStates) {
+			continue
+		}
+		states[metrics.TCPStates[st-1]]++
+	}
+	return scanner.Err()
+}
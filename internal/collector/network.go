@@ -25,42 +25,118 @@
 package collector
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/phuonguno98/unostat/internal/config"
 	"github.com/phuonguno98/unostat/pkg/metrics"
 	"github.com/shirou/gopsutil/v3/net"
 )
 
-// NetworkCollector collects network bandwidth metrics.
+func init() {
+	Register("network", func(cfg *config.Config) (Collector, error) {
+		c, err := NewNetworkCollector(cfg.IncludeNetworks, cfg.ExcludeNetworks)
+		if err != nil {
+			return nil, err
+		}
+		c.counter32 = cfg.CounterWrap32
+		c.physicalOnly = cfg.PhysicalOnly
+		c.sysRoot = cfg.Host.SysRoot()
+		return c, nil
+	})
+}
+
+// NetworkSample holds the metrics of a single interface.
+type NetworkSample struct {
+	Interface string
+	Stats     metrics.NetStats
+}
+
+// Apply stores the interface metrics in the snapshot.
+func (s NetworkSample) Apply(snapshot *metrics.Snapshot) {
+	if snapshot.Networks == nil {
+		snapshot.Networks = make(map[string]metrics.NetStats)
+	}
+	snapshot.Networks[s.Interface] = s.Stats
+}
+
+// NetworkCollector collects network bandwidth, packet, error and drop metrics.
 type NetworkCollector struct {
-	prevStats         map[string]metrics.NetworkIOStats
-	includeInterfaces []string // Interfaces to monitor (empty = all)
-	excludeInterfaces []string // Interfaces to exclude
-	firstRun          bool
+	prevStats    map[string]metrics.NetworkIOStats
+	filter       nameFilter      // Include/exclude interface patterns
+	physicalOnly bool            // Skip virtual interfaces (veth, bridges, docker...)
+	sysRoot      string          // Mountpoint of sysfs, to tell virtual interfaces apart
+	loopbacks    map[string]bool // Loopback flag of every interface seen, by name
+	counter32    bool            // Counters wrap around at 2^32
+	resets       []string        // Interfaces whose counters were reset during the last collection
+	firstRun     bool
+}
+
+// Dependency injection point for testing
+var listInterfaces = net.Interfaces
+
+// virtualInterfacePrefixes lists the names of virtual network interfaces, used where sysfs is not available.
+var virtualInterfacePrefixes = []string{
+	"veth", "docker", "br-", "virbr", "bridge", "cali", "flannel", "cni", "vxlan", "tun", "tap", "utun",
+	"awdl", "llw", "vEthernet",
 }
 
 // NewNetworkCollector creates a new network collector instance.
-// includeInterfaces: list of interface names to monitor (empty = all available)
-// excludeInterfaces: list of interface names to exclude
-func NewNetworkCollector(includeInterfaces, excludeInterfaces []string) *NetworkCollector {
-	return &NetworkCollector{
-		prevStats:         make(map[string]metrics.NetworkIOStats),
-		includeInterfaces: includeInterfaces,
-		excludeInterfaces: excludeInterfaces,
-		firstRun:          true,
+// includeInterfaces: list of interface patterns to monitor (empty = all available)
+// excludeInterfaces: list of interface patterns to exclude
+// Patterns are exact names, globs (e.g., "eth*") or regular expressions prefixed with "re:".
+// Returns an error if a pattern is invalid.
+func NewNetworkCollector(includeInterfaces, excludeInterfaces []string) (*NetworkCollector, error) {
+	filter, err := newNameFilter(includeInterfaces, excludeInterfaces)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network filter: %w", err)
 	}
+	return &NetworkCollector{
+		prevStats: make(map[string]metrics.NetworkIOStats),
+		filter:    filter,
+		sysRoot:   config.DefaultSysRoot,
+		loopbacks: make(map[string]bool),
+		firstRun:  true,
+	}, nil
+}
+
+// Init takes the baseline network I/O counters.
+func (n *NetworkCollector) Init() error {
+	_, err := n.collectStats()
+	return err
 }
 
 // Collect gathers current network I/O metrics.
-// Returns map of interface names to NetStats.
-func (n *NetworkCollector) Collect() (map[string]metrics.NetStats, error) {
+// Returns one NetworkSample per monitored interface.
+// The first call without a prior Init only stores the baseline and returns no samples.
+func (n *NetworkCollector) Collect(_ context.Context) ([]Sample, error) {
+	stats, err := n.collectStats()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]Sample, 0, len(stats)+len(n.resets))
+	for name, st := range stats {
+		samples = append(samples, NetworkSample{Interface: name, Stats: st})
+	}
+	for _, name := range n.resets {
+		samples = append(samples, CounterResetSample{Source: "network/" + name})
+	}
+	return samples, nil
+}
+
+// collectStats reads the current network I/O counters and computes metrics against the previous ones.
+// Returns map of interface names to NetStats, or nil on the baseline run.
+func (n *NetworkCollector) collectStats() (map[string]metrics.NetStats, error) {
 	ioCounters, err := net.IOCounters(true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get network I/O counters: %w", err)
 	}
 
 	result := make(map[string]metrics.NetStats)
+	n.resets = n.resets[:0]
 	now := time.Now()
 
 	for _, counter := range ioCounters {
@@ -77,9 +153,16 @@ func (n *NetworkCollector) Collect() (map[string]metrics.NetStats, error) {
 		}
 
 		currentStats := metrics.NetworkIOStats{
-			BytesSent: counter.BytesSent,
-			BytesRecv: counter.BytesRecv,
-			Timestamp: now,
+			BytesSent:   counter.BytesSent,
+			BytesRecv:   counter.BytesRecv,
+			PacketsSent: counter.PacketsSent,
+			PacketsRecv: counter.PacketsRecv,
+			ErrIn:       counter.Errin,
+			ErrOut:      counter.Errout,
+			DropIn:      counter.Dropin,
+			DropOut:     counter.Dropout,
+			Counter32:   n.counter32,
+			Timestamp:   now,
 		}
 
 		// First run - just store baseline
@@ -95,11 +178,31 @@ func (n *NetworkCollector) Collect() (map[string]metrics.NetStats, error) {
 			continue
 		}
 
-		// Calculate bandwidth
+		// A re-created interface starts its counters from zero; re-baseline it
+		// and report it as N/A for this interval
+		if metrics.NetworkCountersReset(prevStats, currentStats) {
+			n.prevStats[interfaceName] = currentStats
+			n.resets = append(n.resets, interfaceName)
+			continue
+		}
+
+		// Calculate bandwidth, packet, error and drop rates
 		bandwidth := metrics.CalculateNetworkBandwidth(prevStats, currentStats)
+		rxBandwidth, txBandwidth := metrics.CalculateNetworkRxTxBandwidth(prevStats, currentStats)
+		rxPackets, txPackets := metrics.CalculateNetworkPacketRates(prevStats, currentStats)
+		errIn, errOut := metrics.CalculateNetworkErrorRates(prevStats, currentStats)
+		dropIn, dropOut := metrics.CalculateNetworkDropRates(prevStats, currentStats)
 
 		result[interfaceName] = metrics.NetStats{
-			Bandwidth: bandwidth,
+			Bandwidth:   bandwidth,
+			RxBandwidth: rxBandwidth,
+			TxBandwidth: txBandwidth,
+			RxPackets:   rxPackets,
+			TxPackets:   txPackets,
+			ErrIn:       errIn,
+			ErrOut:      errOut,
+			DropIn:      dropIn,
+			DropOut:     dropOut,
 		}
 
 		// Update previous stats
@@ -115,6 +218,7 @@ func (n *NetworkCollector) Collect() (map[string]metrics.NetStats, error) {
 }
 
 // isLoopback checks if an interface is a loopback interface.
+// Interfaces are identified by their loopback flag, refreshed whenever an unknown interface appears.
 func (n *NetworkCollector) isLoopback(interfaceName string) bool {
 	// Common loopback interface names
 	loopbacks := []string{"lo", "lo0", "Loopback"}
@@ -123,33 +227,27 @@ func (n *NetworkCollector) isLoopback(interfaceName string) bool {
 			return true
 		}
 	}
-	return false
-}
 
-// shouldMonitor checks if an interface should be monitored based on include/exclude filters.
-func (n *NetworkCollector) shouldMonitor(interfaceName string) bool {
-	// Check exclude list first
-	if len(n.excludeInterfaces) > 0 {
-		for _, excluded := range n.excludeInterfaces {
-			if excluded == interfaceName {
-				return false
+	loopback, known := n.loopbacks[interfaceName]
+	if !known {
+		n.loopbacks[interfaceName] = false // Not listed (e.g. removed meanwhile): don't refresh again
+		if interfaces, err := listInterfaces(); err == nil {
+			for _, iface := range interfaces {
+				n.loopbacks[iface.Name] = slices.Contains(iface.Flags, "loopback")
 			}
 		}
+		loopback = n.loopbacks[interfaceName]
 	}
+	return loopback
+}
 
-	// If include list is empty, monitor all (except excluded)
-	if len(n.includeInterfaces) == 0 {
-		return true
-	}
-
-	// Check include list
-	for _, included := range n.includeInterfaces {
-		if included == interfaceName {
-			return true
-		}
+// shouldMonitor checks if an interface should be monitored based on include/exclude filters
+// and, in physical-only mode, on whether it is virtual.
+func (n *NetworkCollector) shouldMonitor(interfaceName string) bool {
+	if n.physicalOnly && isVirtualDevice(n.sysRoot, "net", interfaceName, virtualInterfacePrefixes) {
+		return false
 	}
-
-	return false
+	return n.filter.allows(interfaceName)
 }
 
 // Name returns the collector name for logging purposes.
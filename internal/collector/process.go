@@ -0,0 +1,307 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+func init() {
+	Register("process", func(cfg *config.Config) (Collector, error) {
+		if len(cfg.ProcessPIDs)+len(cfg.ProcessNames)+len(cfg.ProcessCmdlines) == 0 {
+			return nil, nil // Nothing to monitor
+		}
+		return NewProcessCollector(cfg.ProcessPIDs, cfg.ProcessNames, cfg.ProcessCmdlines)
+	})
+}
+
+// processInfo identifies a running process for selector matching.
+type processInfo struct {
+	PID     int32
+	Name    string
+	Cmdline string
+}
+
+// processCounters holds the raw resource usage of a single process.
+type processCounters struct {
+	CreateTime int64 // Milliseconds since the epoch; distinguishes a reused PID
+	RSS        uint64
+	Threads    int64
+	FDs        int64
+	IO         metrics.ProcessIOStats
+}
+
+// Dependency injection points for testing
+var (
+	listProcesses = listSystemProcesses
+	readProcess   = readSystemProcess
+)
+
+// processSelector selects the processes reported under one key.
+type processSelector struct {
+	key     string         // Series key, e.g. "pid:1234", "name:nginx"
+	pid     int32          // Exact PID (0 = unused)
+	name    *regexp.Regexp // Process name pattern (nil = unused)
+	cmdline string         // Command line substring (empty = unused)
+}
+
+// matches reports whether a process is selected.
+func (s *processSelector) matches(info processInfo) bool {
+	switch {
+	case s.pid != 0:
+		return info.PID == s.pid
+	case s.name != nil:
+		return s.name.MatchString(info.Name)
+	default:
+		return strings.Contains(info.Cmdline, s.cmdline)
+	}
+}
+
+// processKey identifies a process instance across collections.
+type processKey struct {
+	pid     int32
+	created int64
+}
+
+// ProcessSample holds the aggregated metrics of the processes matched by a selector.
+type ProcessSample struct {
+	Selector string
+	Stats    metrics.ProcessStats
+}
+
+// Apply stores the process metrics in the snapshot.
+func (s ProcessSample) Apply(snapshot *metrics.Snapshot) {
+	if snapshot.Processes == nil {
+		snapshot.Processes = make(map[string]metrics.ProcessStats)
+	}
+	snapshot.Processes[s.Selector] = s.Stats
+}
+
+// ProcessCollector collects the resource usage of selected processes.
+// Selectors are resolved again at every collection, so a service that restarts under
+// a new PID keeps being reported under the same name or command line selector.
+type ProcessCollector struct {
+	selectors   []processSelector
+	prevStats   map[processKey]metrics.ProcessIOStats
+	listNeeded  bool // A selector needs the process list
+	withCmdline bool // A selector needs command lines
+}
+
+// NewProcessCollector creates a new process collector instance.
+// pids: exact PIDs to monitor
+// names: regular expressions matched against the process name
+// cmdlines: substrings matched against the full command line
+func NewProcessCollector(pids []int32, names, cmdlines []string) (*ProcessCollector, error) {
+	c := &ProcessCollector{prevStats: make(map[processKey]metrics.ProcessIOStats)}
+
+	for _, pid := range pids {
+		c.selectors = append(c.selectors, processSelector{key: "pid:" + strconv.Itoa(int(pid)), pid: pid})
+	}
+	for _, pattern := range names {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid process name pattern %q: %w", pattern, err)
+		}
+		c.selectors = append(c.selectors, processSelector{key: "name:" + pattern, name: re})
+		c.listNeeded = true
+	}
+	for _, cmdline := range cmdlines {
+		c.selectors = append(c.selectors, processSelector{key: "cmdline:" + cmdline, cmdline: cmdline})
+		c.listNeeded = true
+		c.withCmdline = true
+	}
+
+	return c, nil
+}
+
+// Init takes the baseline CPU, I/O and context switch counters of the matching processes.
+func (p *ProcessCollector) Init() error {
+	_, err := p.Collect(context.Background())
+	return err
+}
+
+// Collect gathers the current metrics of every selector.
+// Returns one ProcessSample per selector that currently matches at least one process.
+// A process seen for the first time contributes its RSS, threads and FDs but no rates
+// until the next collection.
+func (p *ProcessCollector) Collect(ctx context.Context) ([]Sample, error) {
+	var candidates []processInfo
+	if p.listNeeded {
+		var err error
+		candidates, err = listProcesses(ctx, p.withCmdline)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list processes: %w", err)
+		}
+	}
+
+	now := time.Now()
+	counters := make(map[int32]*processCounters) // Read each process once, nil if it vanished
+	read := func(pid int32) *processCounters {
+		if c, ok := counters[pid]; ok {
+			return c
+		}
+		c, err := readProcess(ctx, pid)
+		if err != nil {
+			counters[pid] = nil
+			return nil
+		}
+		c.IO.Timestamp = now
+		counters[pid] = &c
+		return &c
+	}
+
+	nextStats := make(map[processKey]metrics.ProcessIOStats)
+	samples := make([]Sample, 0, len(p.selectors))
+
+	for i := range p.selectors {
+		sel := &p.selectors[i]
+
+		var pids []int32
+		if sel.pid != 0 {
+			pids = []int32{sel.pid}
+		} else {
+			for _, info := range candidates {
+				if sel.matches(info) {
+					pids = append(pids, info.PID)
+				}
+			}
+		}
+
+		var stats metrics.ProcessStats
+		reset := false
+		for _, pid := range pids {
+			c := read(pid)
+			if c == nil {
+				continue
+			}
+
+			stats.Count++
+			stats.RSS += c.RSS
+			stats.Threads += c.Threads
+			stats.FDs += c.FDs
+
+			key := processKey{pid: pid, created: c.CreateTime}
+			nextStats[key] = c.IO
+			if prev, ok := p.prevStats[key]; ok {
+				// A reset process has no valid delta this interval; it only contributes its baseline
+				if metrics.ProcessCountersReset(prev, c.IO) {
+					reset = true
+					continue
+				}
+				cpu, readBytes, writeBytes, ctxSwitches := metrics.CalculateProcessRates(prev, c.IO)
+				stats.CPU += cpu
+				stats.ReadBytes += readBytes
+				stats.WriteBytes += writeBytes
+				stats.CtxSwitches += ctxSwitches
+			}
+		}
+
+		if stats.Count > 0 {
+			samples = append(samples, ProcessSample{Selector: sel.key, Stats: stats})
+		}
+		if reset {
+			samples = append(samples, CounterResetSample{Source: "process/" + sel.key})
+		}
+	}
+
+	// Exited processes are dropped from the baseline
+	p.prevStats = nextStats
+
+	return samples, nil
+}
+
+// Name returns the collector name for logging purposes.
+func (p *ProcessCollector) Name() string {
+	return "Process"
+}
+
+// listSystemProcesses lists the running processes with their names and, if requested, command lines.
+// Processes that exit while being listed are skipped.
+func listSystemProcesses(ctx context.Context, withCmdline bool) ([]processInfo, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]processInfo, 0, len(procs))
+	for _, proc := range procs {
+		name, err := proc.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		info := processInfo{PID: proc.Pid, Name: name}
+		if withCmdline {
+			info.Cmdline, _ = proc.CmdlineWithContext(ctx) // Empty for kernel threads
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// readSystemProcess reads the resource usage of a process.
+// Counters that require privileges the collector does not have (e.g. I/O of another
+// user's process) are reported as zero; only a vanished process is an error.
+func readSystemProcess(ctx context.Context, pid int32) (processCounters, error) {
+	proc, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return processCounters{}, err
+	}
+
+	created, err := proc.CreateTimeWithContext(ctx)
+	if err != nil {
+		return processCounters{}, err
+	}
+
+	c := processCounters{CreateTime: created}
+	if times, err := proc.TimesWithContext(ctx); err == nil {
+		c.IO.CPUTime = times.User + times.System
+	}
+	if mem, err := proc.MemoryInfoWithContext(ctx); err == nil {
+		c.RSS = mem.RSS
+	}
+	if threads, err := proc.NumThreadsWithContext(ctx); err == nil {
+		c.Threads = int64(threads)
+	}
+	if fds, err := proc.NumFDsWithContext(ctx); err == nil {
+		c.FDs = int64(fds)
+	}
+	if io, err := proc.IOCountersWithContext(ctx); err == nil {
+		c.IO.ReadBytes = io.ReadBytes
+		c.IO.WriteBytes = io.WriteBytes
+	}
+	if switches, err := proc.NumCtxSwitchesWithContext(ctx); err == nil {
+		c.IO.CtxSwitches = uint64(switches.Voluntary + switches.Involuntary)
+	}
+	return c, nil
+}
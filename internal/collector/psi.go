@@ -0,0 +1,188 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package collector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func init() {
+	Register("psi", func(cfg *config.Config) (Collector, error) {
+		if runtime.GOOS != "linux" {
+			return nil, nil // Linux only
+		}
+		return NewPSICollector(cfg.Host.ProcRoot()), nil
+	})
+}
+
+// pressureResources lists the resources reported under /proc/pressure.
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// PressureSample holds the stall metrics of a single resource.
+type PressureSample struct {
+	Resource string
+	Stats    metrics.PressureStats
+}
+
+// Apply stores the pressure metrics in the snapshot.
+func (s PressureSample) Apply(snapshot *metrics.Snapshot) {
+	if snapshot.Pressure == nil {
+		snapshot.Pressure = make(map[string]metrics.PressureStats)
+	}
+	snapshot.Pressure[s.Resource] = s.Stats
+}
+
+// pressureReading is a parsed /proc/pressure file.
+type pressureReading struct {
+	counters metrics.PressureCounters
+	stats    metrics.PressureStats // Kernel running averages only
+}
+
+// PSICollector collects Pressure Stall Information, which shows contention on CPU,
+// memory and I/O that utilization percentages don't (requires Linux 4.20+ with PSI enabled).
+type PSICollector struct {
+	procRoot string                              // Mountpoint of procfs
+	prev     map[string]metrics.PressureCounters // Key: resource
+}
+
+// NewPSICollector creates a new PSI collector reading procfs mounted at procRoot.
+func NewPSICollector(procRoot string) *PSICollector {
+	return &PSICollector{
+		procRoot: procRoot,
+		prev:     make(map[string]metrics.PressureCounters),
+	}
+}
+
+// Init checks that PSI is available and takes the baseline stall times.
+func (p *PSICollector) Init() error {
+	if _, err := p.Collect(context.Background()); err != nil {
+		return err
+	}
+	if len(p.prev) == 0 {
+		return fmt.Errorf("pressure stall information not available under %s", filepath.Join(p.procRoot, "pressure"))
+	}
+	return nil
+}
+
+// Collect gathers the stall times of every resource.
+// Returns one PressureSample per resource; the first reading of a resource only stores the baseline.
+// Resources the kernel does not report are skipped.
+func (p *PSICollector) Collect(_ context.Context) ([]Sample, error) {
+	now := time.Now()
+	samples := make([]Sample, 0, len(pressureResources))
+	for _, resource := range pressureResources {
+		reading, err := readPressure(filepath.Join(p.procRoot, "pressure", resource))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s pressure: %w", resource, err)
+		}
+		reading.counters.Timestamp = now
+
+		prev, ok := p.prev[resource]
+		p.prev[resource] = reading.counters
+		if !ok {
+			continue // Baseline only
+		}
+		if metrics.PressureCountersReset(prev, reading.counters) {
+			samples = append(samples, CounterResetSample{Source: "pressure/" + resource}) // N/A for this interval
+			continue
+		}
+
+		stats := reading.stats
+		stats.Some, stats.Full = metrics.CalculatePressureStall(prev, reading.counters)
+		samples = append(samples, PressureSample{Resource: resource, Stats: stats})
+	}
+
+	return samples, nil
+}
+
+// Name returns the collector name for logging purposes.
+func (p *PSICollector) Name() string {
+	return "PSI"
+}
+
+// readPressure parses a PSI file:
+//
+//	some avg10=0.12 avg60=0.05 avg300=0.01 total=123456
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=6789
+//
+// The full line is missing for cpu on kernels before 5.13.
+func readPressure(path string) (pressureReading, error) {
+	reading := pressureReading{stats: metrics.PressureStats{Full: -1, FullAvg10: -1, FullAvg60: -1}}
+	file, err := os.Open(path)
+	if err != nil {
+		return reading, err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || (fields[0] != "some" && fields[0] != "full") {
+			continue
+		}
+
+		var avg10, avg60 float64
+		var total uint64
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			switch key {
+			case "avg10":
+				avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				avg60, err = strconv.ParseFloat(value, 64)
+			case "total":
+				total, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return reading, fmt.Errorf("invalid %s line %q: %w", fields[0], scanner.Text(), err)
+			}
+		}
+
+		if fields[0] == "some" {
+			reading.stats.SomeAvg10, reading.stats.SomeAvg60 = avg10, avg60
+			reading.counters.SomeTotal = total
+		} else {
+			reading.stats.FullAvg10, reading.stats.FullAvg60 = avg10, avg60
+			reading.counters.FullTotal = total
+			reading.counters.HasFull = true
+		}
+	}
+	return reading, scanner.Err()
+}
@@ -0,0 +1,173 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func init() {
+	Register("system", func(cfg *config.Config) (Collector, error) {
+		if runtime.GOOS != "linux" {
+			return nil, nil // Linux only
+		}
+		return NewSystemCollector(cfg.Host.ProcRoot()), nil
+	})
+}
+
+// SystemSample holds the kernel activity metrics of an interval.
+type SystemSample struct {
+	Stats metrics.SystemStats
+}
+
+// Apply stores the kernel activity metrics in the snapshot.
+func (s SystemSample) Apply(snapshot *metrics.Snapshot) {
+	stats := s.Stats
+	snapshot.System = &stats
+}
+
+// SystemCollector collects load averages, run queue length and context switch, interrupt
+// and fork rates, which reveal scheduler saturation that CPU utilization alone doesn't.
+type SystemCollector struct {
+	procRoot string // Mountpoint of procfs
+	prev     metrics.SystemCounters
+}
+
+// NewSystemCollector creates a new kernel activity collector reading procfs mounted at procRoot.
+func NewSystemCollector(procRoot string) *SystemCollector {
+	return &SystemCollector{procRoot: procRoot}
+}
+
+// Init takes the baseline kernel counters.
+func (s *SystemCollector) Init() error {
+	_, err := s.Collect(context.Background())
+	return err
+}
+
+// Collect gathers the current kernel activity and calculates rates.
+// Returns a single SystemSample.
+// The first call without a prior Init only stores the baseline and returns no samples.
+func (s *SystemCollector) Collect(_ context.Context) ([]Sample, error) {
+	var stats metrics.SystemStats
+	counters, err := readProcStat(filepath.Join(s.procRoot, "stat"), &stats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kernel counters: %w", err)
+	}
+	counters.Timestamp = time.Now()
+
+	if err := readLoadAvg(filepath.Join(s.procRoot, "loadavg"), &stats); err != nil {
+		return nil, fmt.Errorf("failed to read load average: %w", err)
+	}
+
+	prev := s.prev
+	s.prev = counters
+	if prev.Timestamp.IsZero() {
+		return nil, nil // Baseline only
+	}
+	if metrics.SystemCountersReset(prev, counters) {
+		return []Sample{CounterResetSample{Source: "system"}}, nil // N/A for this interval
+	}
+
+	stats.ContextSwitches, stats.Interrupts, stats.Forks = metrics.CalculateSystemRates(prev, counters)
+	return []Sample{SystemSample{Stats: stats}}, nil
+}
+
+// Name returns the collector name for logging purposes.
+func (s *SystemCollector) Name() string {
+	return "System"
+}
+
+// readProcStat parses the kernel activity lines of /proc/stat:
+// the cumulative ctxt, intr (first field is the total) and processes counters,
+// and the instantaneous procs_running and procs_blocked gauges stored in stats.
+func readProcStat(path string, stats *metrics.SystemStats) (metrics.SystemCounters, error) {
+	var counters metrics.SystemCounters
+	file, err := os.Open(path)
+	if err != nil {
+		return counters, err
+	}
+	defer func() { _ = file.Close() }()
+
+	fields := map[string]*uint64{
+		"ctxt":          &counters.ContextSwitches,
+		"intr":          &counters.Interrupts,
+		"processes":     &counters.Forks,
+		"procs_running": &stats.ProcsRunning,
+		"procs_blocked": &stats.ProcsBlocked,
+	}
+	found := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // The intr line lists every IRQ
+	for scanner.Scan() {
+		line := strings.Fields(scanner.Text())
+		if len(line) < 2 {
+			continue
+		}
+		field, ok := fields[line[0]]
+		if !ok {
+			continue
+		}
+		if *field, err = strconv.ParseUint(line[1], 10, 64); err != nil {
+			return counters, fmt.Errorf("invalid %s line: %w", line[0], err)
+		}
+		found++
+	}
+	if err := scanner.Err(); err != nil {
+		return counters, err
+	}
+	if found < len(fields) {
+		return counters, fmt.Errorf("missing kernel counters in %s", path)
+	}
+	return counters, nil
+}
+
+// readLoadAvg parses /proc/loadavg ("0.52 0.58 0.59 2/1183 12345") into stats.
+func readLoadAvg(path string, stats *metrics.SystemStats) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return fmt.Errorf("invalid load average %q", strings.TrimSpace(string(data)))
+	}
+	for i, load := range []*float64{&stats.Load1, &stats.Load5, &stats.Load15} {
+		if *load, err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return fmt.Errorf("invalid load average %q: %w", fields[i], err)
+		}
+	}
+	return nil
+}
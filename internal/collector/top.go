@@ -0,0 +1,171 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+func init() {
+	Register("top", func(cfg *config.Config) (Collector, error) {
+		if cfg.TopProcesses <= 0 {
+			return nil, nil // Disabled
+		}
+		return NewTopCollector(cfg.TopProcesses), nil
+	})
+}
+
+// processUsage is the CPU time and memory of a process, used for ranking.
+type processUsage struct {
+	PID        int32
+	Name       string
+	CreateTime int64   // Milliseconds since the epoch; distinguishes a reused PID
+	CPUTime    float64 // User + system CPU time in seconds
+	RSS        uint64
+}
+
+// Dependency injection point for testing
+var listProcessUsage = listSystemProcessUsage
+
+// TopSample holds the heaviest processes of an interval.
+type TopSample struct {
+	Top metrics.TopProcesses
+}
+
+// Apply stores the ranking in the snapshot.
+func (s TopSample) Apply(snapshot *metrics.Snapshot) {
+	top := s.Top
+	snapshot.TopProcesses = &top
+}
+
+// TopCollector ranks all processes by CPU utilization and resident memory at every interval,
+// so the process behind a spike can be identified afterwards.
+type TopCollector struct {
+	n        int
+	prevCPU  map[processKey]float64 // CPU time of every process at the previous collection
+	prevTime time.Time
+}
+
+// NewTopCollector creates a collector recording the n heaviest processes by CPU and by RSS.
+func NewTopCollector(n int) *TopCollector {
+	return &TopCollector{n: n, prevCPU: make(map[processKey]float64)}
+}
+
+// Init takes the baseline CPU times of all processes.
+func (t *TopCollector) Init() error {
+	_, err := t.Collect(context.Background())
+	return err
+}
+
+// Collect ranks the running processes.
+// Processes started since the previous collection are ranked by RSS only.
+// The first call without a prior Init only stores the baseline and returns no samples.
+func (t *TopCollector) Collect(ctx context.Context) ([]Sample, error) {
+	usage, err := listProcessUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	now := time.Now()
+	deltaTime := now.Sub(t.prevTime).Seconds()
+	firstRun := t.prevTime.IsZero()
+
+	var top metrics.TopProcesses
+	nextCPU := make(map[processKey]float64, len(usage))
+	for _, u := range usage {
+		key := processKey{pid: u.PID, created: u.CreateTime}
+		nextCPU[key] = u.CPUTime
+
+		if prev, ok := t.prevCPU[key]; ok && deltaTime > 0 && u.CPUTime > prev {
+			top.ByCPU = append(top.ByCPU, metrics.TopProcess{PID: u.PID, Name: u.Name, Value: (u.CPUTime - prev) / deltaTime * 100.0})
+		}
+		if u.RSS > 0 {
+			top.ByRSS = append(top.ByRSS, metrics.TopProcess{PID: u.PID, Name: u.Name, Value: float64(u.RSS)})
+		}
+	}
+	t.prevCPU = nextCPU
+	t.prevTime = now
+
+	if firstRun {
+		return nil, nil // Baseline only
+	}
+
+	top.ByCPU = topN(top.ByCPU, t.n)
+	top.ByRSS = topN(top.ByRSS, t.n)
+	return []Sample{TopSample{Top: top}}, nil
+}
+
+// topN sorts processes by descending value (ties by PID) and keeps the first n.
+func topN(procs []metrics.TopProcess, n int) []metrics.TopProcess {
+	sort.Slice(procs, func(i, j int) bool {
+		if procs[i].Value != procs[j].Value {
+			return procs[i].Value > procs[j].Value
+		}
+		return procs[i].PID < procs[j].PID
+	})
+	if len(procs) > n {
+		procs = procs[:n]
+	}
+	return procs
+}
+
+// Name returns the collector name for logging purposes.
+func (t *TopCollector) Name() string {
+	return "Top"
+}
+
+// listSystemProcessUsage reads the CPU time and RSS of every running process.
+// Processes that exit while being listed are skipped.
+func listSystemProcessUsage(ctx context.Context) ([]processUsage, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]processUsage, 0, len(procs))
+	for _, proc := range procs {
+		created, err := proc.CreateTimeWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		u := processUsage{PID: proc.Pid, CreateTime: created}
+		u.Name, _ = proc.NameWithContext(ctx)
+		if times, err := proc.TimesWithContext(ctx); err == nil {
+			u.CPUTime = times.User + times.System
+		}
+		if mem, err := proc.MemoryInfoWithContext(ctx); err == nil {
+			u.RSS = mem.RSS
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
@@ -30,6 +30,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -41,11 +43,68 @@ type Config struct {
 	BufferSize       int           // Number of records to buffer before flush
 	FlushInterval    time.Duration // Maximum time before forcing a flush
 
+	// Sinks
+	Sinks         []string // Exporters to write snapshots to (empty = csv)
+	SinkQueueSize int      // Snapshots queued per sink before dropping (0 = default)
+
+	// CSV
+	OnSchemaMismatch string // Policy when an existing output file has a different header: rotate, fail
+	Fsync            string // When to fsync the output file: never, on-flush, every
+	FsyncEvery       int    // Records between fsyncs when Fsync is "every"
+
+	// Rotation and retention
+	RotateSize       int64         // Rotate output files at this size in bytes (0 = DefaultMaxOutputFileSize)
+	RotateInterval   string        // Rotate on wall clock boundaries: hourly, daily (empty = disabled)
+	FileNameTemplate string        // Rotated file name template with {host}, {start}, {index} (empty = <output>_<N>)
+	MaxFiles         int           // Keep at most this many output files (0 = unlimited)
+	MaxAge           time.Duration // Delete output files older than this (0 = unlimited)
+	Compress         string        // Compression of rotated files: none, gzip
+
+	// Prometheus
+	PrometheusListen string // Listen address of the /metrics endpoint (empty = disabled)
+
+	// InfluxDB
+	InfluxURL       string // Base URL of an /api/v2/write compatible endpoint (empty = write to a local .lp file)
+	InfluxToken     string // API token sent in the Authorization header
+	InfluxOrg       string // Organization query parameter
+	InfluxBucket    string // Bucket query parameter
+	InfluxSpoolPath string // Spool file for undelivered batches (empty = <output>.spool.lp)
+
+	// CPU
+	PerCPU bool // Collect utilization and iowait of every logical core
+
+	// Processes
+	ProcessPIDs     []int32  // PIDs to monitor
+	ProcessNames    []string // Regular expressions matched against process names
+	ProcessCmdlines []string // Substrings matched against process command lines
+	TopProcesses    int      // Record the N heaviest processes by CPU and RSS per interval (0 = disabled)
+
+	// Cgroups
+	CgroupRoot     string   // Mountpoint of the cgroup v2 hierarchy (empty = <sysfs>/fs/cgroup)
+	CgroupPaths    []string // Cgroups to monitor, relative to CgroupRoot
+	CgroupDiscover bool     // Monitor the cgroups of running containers
+
+	// Host
+	Host HostPaths // Filesystems of the monitored host (zero value = the local /, /proc and /sys)
+
+	// Counters
+	CounterWrap32 bool // Treat decreasing disk and network counters as 32-bit wraparounds rather than resets
+
 	// Filters
 	IncludeDisks    []string // Disk devices to monitor (empty = all)
 	ExcludeDisks    []string // Disk devices to exclude
 	IncludeNetworks []string // Network interfaces to monitor (empty = all)
 	ExcludeNetworks []string // Network interfaces to exclude
+	PhysicalOnly    bool     // Skip virtual disks and network interfaces
+
+	IncludeMountpoints []string // Filesystem mountpoints to monitor (empty = all)
+	ExcludeMountpoints []string // Filesystem mountpoints to exclude
+	IncludeFstypes     []string // Filesystem types to monitor (empty = all non-pseudo)
+	ExcludeFstypes     []string // Filesystem types to exclude
+
+	// Collectors
+	IncludeCollectors []string // Collectors to enable by name (empty = all registered)
+	ExcludeCollectors []string // Collectors to disable by name
 
 	// Logging
 	LogLevel string // Log level: debug, info, warn, error
@@ -58,6 +117,31 @@ type Config struct {
 	ListDevices bool // List available disks and network interfaces
 }
 
+// Schema mismatch policies for appending to an existing CSV file.
+const (
+	SchemaMismatchRotate = "rotate" // Continue in a new rotated file
+	SchemaMismatchFail   = "fail"   // Stop with an error
+)
+
+// Fsync policies for the CSV output file.
+const (
+	FsyncNever   = "never"    // Leave durability to the operating system
+	FsyncOnFlush = "on-flush" // Sync after every buffer flush
+	FsyncEvery   = "every"    // Flush and sync after every FsyncEvery records
+)
+
+// Wall clock rotation intervals.
+const (
+	RotateHourly = "hourly"
+	RotateDaily  = "daily"
+)
+
+// Compression of rotated output files.
+const (
+	CompressNone = "none"
+	CompressGzip = "gzip"
+)
+
 // Default configuration values.
 const (
 	DefaultSamplingInterval  = 30 * time.Second
@@ -65,35 +149,98 @@ const (
 	DefaultFlushInterval     = 5 * time.Second
 	DefaultLogLevel          = "info"
 	DefaultMaxOutputFileSize = 150 * 1024 * 1024 // 150MB
+	DefaultSinkQueueSize     = 100
+	DefaultPrometheusListen  = ":9273"
+	DefaultOnSchemaMismatch  = SchemaMismatchRotate
 )
 
 // GetDefaultOutputPath generates default output path: <hostname>_<timestamp>.csv
 func GetDefaultOutputPath() string {
+	timestamp := time.Now().Format("20060102150405")
+	filename := fmt.Sprintf("%s_%s.csv", SanitizedHostname(), timestamp)
+
+	// Get executable directory
+	exePath, err := os.Executable()
+	if err != nil {
+		// Fallback to current directory
+		return filename
+	}
+
+	exeDir := filepath.Dir(exePath)
+	return filepath.Join(exeDir, filename)
+}
+
+// SanitizedHostname returns the hostname with characters that are invalid in file names replaced.
+func SanitizedHostname() string {
 	hostname, err := os.Hostname()
 	if err != nil {
 		hostname = "unknown"
 	}
 
 	// Clean hostname (remove invalid filename characters)
-	hostname = strings.Map(func(r rune) rune {
+	return strings.Map(func(r rune) rune {
 		if r == '/' || r == '\\' || r == ':' || r == '*' || r == '?' || r == '"' || r == '<' || r == '>' || r == '|' {
 			return '_'
 		}
 		return r
 	}, hostname)
+}
 
-	timestamp := time.Now().Format("20060102150405")
-	filename := fmt.Sprintf("%s_%s.csv", hostname, timestamp)
+// ParseSize parses a human-readable size such as "150MB", "1GB", "512KiB" or "1048576" into bytes.
+// Units are binary multiples (1KB = 1024 bytes).
+func ParseSize(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	if str == "" {
+		return 0, errors.New("empty size")
+	}
 
-	// Get executable directory
-	exePath, err := os.Executable()
-	if err != nil {
-		// Fallback to current directory
-		return filename
+	units := []struct {
+		suffix string
+		mult   int64
+	}{
+		{"TIB", 1 << 40}, {"GIB", 1 << 30}, {"MIB", 1 << 20}, {"KIB", 1 << 10},
+		{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+		{"B", 1},
 	}
 
-	exeDir := filepath.Dir(exePath)
-	return filepath.Join(exeDir, filename)
+	mult := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(str, u.suffix) {
+			mult = u.mult
+			str = strings.TrimSpace(strings.TrimSuffix(str, u.suffix))
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size: %s", s)
+	}
+
+	return int64(value * float64(mult)), nil
+}
+
+// ParseFsync parses an fsync policy: "never", "on-flush" or "every-N" (e.g. "every-10").
+// It returns the policy and, for "every-N", the number of records between syncs.
+func ParseFsync(s string) (string, int, error) {
+	str := strings.ToLower(strings.TrimSpace(s))
+	switch str {
+	case "", FsyncNever:
+		return FsyncNever, 0, nil
+	case FsyncOnFlush:
+		return FsyncOnFlush, 0, nil
+	}
+
+	if n, ok := strings.CutPrefix(str, FsyncEvery+"-"); ok {
+		every, err := strconv.Atoi(n)
+		if err != nil || every < 1 {
+			return "", 0, fmt.Errorf("invalid fsync interval: %s (must be a positive number of records)", s)
+		}
+		return FsyncEvery, every, nil
+	}
+
+	return "", 0, fmt.Errorf("invalid fsync policy: %s (must be %s, %s or %s-N)", s, FsyncNever, FsyncOnFlush, FsyncEvery)
 }
 
 // LoadFromFlags loads configuration from command-line flags.
@@ -207,6 +354,81 @@ func (c *Config) Validate() error {
 		return errors.New("flush interval must be at least 1 second")
 	}
 
+	if c.SinkQueueSize < 0 {
+		return errors.New("sink queue size cannot be negative")
+	}
+
+	if c.RotateSize < 0 {
+		return errors.New("rotate size cannot be negative")
+	}
+
+	switch c.RotateInterval {
+	case "", RotateHourly, RotateDaily:
+	default:
+		return fmt.Errorf("invalid rotate interval: %s (must be %s or %s)", c.RotateInterval, RotateHourly, RotateDaily)
+	}
+
+	if strings.ContainsAny(c.FileNameTemplate, `/\`) {
+		return fmt.Errorf("file name template must not contain path separators: %s", c.FileNameTemplate)
+	}
+
+	if c.MaxFiles < 0 {
+		return errors.New("max files cannot be negative")
+	}
+
+	if c.MaxAge < 0 {
+		return errors.New("max age cannot be negative")
+	}
+
+	switch c.Compress {
+	case "", CompressNone, CompressGzip:
+	default:
+		return fmt.Errorf("invalid compression: %s (must be %s or %s)", c.Compress, CompressNone, CompressGzip)
+	}
+
+	switch c.Fsync {
+	case "", FsyncNever, FsyncOnFlush:
+	case FsyncEvery:
+		if c.FsyncEvery < 1 {
+			return errors.New("fsync interval must be at least 1 record")
+		}
+	default:
+		return fmt.Errorf("invalid fsync policy: %s (must be %s, %s or %s)", c.Fsync, FsyncNever, FsyncOnFlush, FsyncEvery)
+	}
+
+	switch c.OnSchemaMismatch {
+	case "", SchemaMismatchRotate, SchemaMismatchFail:
+	default:
+		return fmt.Errorf("invalid schema mismatch policy: %s (must be %s or %s)",
+			c.OnSchemaMismatch, SchemaMismatchRotate, SchemaMismatchFail)
+	}
+
+	if c.TopProcesses < 0 {
+		return errors.New("top processes cannot be negative")
+	}
+
+	if err := c.Host.Validate(); err != nil {
+		return err
+	}
+
+	for _, pid := range c.ProcessPIDs {
+		if pid <= 0 {
+			return fmt.Errorf("invalid process PID: %d", pid)
+		}
+	}
+
+	for _, pattern := range c.ProcessNames {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid process name pattern %q: %w", pattern, err)
+		}
+	}
+
+	for _, cmdline := range c.ProcessCmdlines {
+		if cmdline == "" {
+			return errors.New("process command line match cannot be empty")
+		}
+	}
+
 	// Validate log level
 	validLogLevels := map[string]bool{
 		"debug": true,
@@ -268,6 +490,6 @@ func (c *Config) ensureOutputDir() error {
 
 // String returns a human-readable representation of the configuration.
 func (c *Config) String() string {
-	return fmt.Sprintf("Config{Interval=%v, Output=%s, BufferSize=%d, FlushInterval=%v}, Timezone=%s",
-		c.SamplingInterval, c.OutputPath, c.BufferSize, c.FlushInterval, c.Timezone)
+	return fmt.Sprintf("Config{Interval=%v, Output=%s, BufferSize=%d, FlushInterval=%v, Sinks=%v}, Timezone=%s",
+		c.SamplingInterval, c.OutputPath, c.BufferSize, c.FlushInterval, c.Sinks, c.Timezone)
 }
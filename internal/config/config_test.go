@@ -189,6 +189,30 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Invalid Process Name Pattern",
+			config: Config{
+				SamplingInterval: 5 * time.Second,
+				OutputPath:       validOutputPath,
+				BufferSize:       100,
+				FlushInterval:    5 * time.Second,
+				LogLevel:         "info",
+				ProcessNames:     []string{"java("},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid Process PID",
+			config: Config{
+				SamplingInterval: 5 * time.Second,
+				OutputPath:       validOutputPath,
+				BufferSize:       100,
+				FlushInterval:    5 * time.Second,
+				LogLevel:         "info",
+				ProcessPIDs:      []int32{-1},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -309,3 +333,147 @@ func TestLoadFromArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"1048576", 1048576, false},
+		{"150MB", 150 * 1024 * 1024, false},
+		{"1gb", 1 << 30, false},
+		{"512KiB", 512 * 1024, false},
+		{"1.5G", 3 << 29, false},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"-1MB", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFsync(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantPolicy string
+		wantEvery  int
+		wantErr    bool
+	}{
+		{"", FsyncNever, 0, false},
+		{"never", FsyncNever, 0, false},
+		{"on-flush", FsyncOnFlush, 0, false},
+		{"every-10", FsyncEvery, 10, false},
+		{"every-0", "", 0, true},
+		{"every-x", "", 0, true},
+		{"always", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			policy, every, err := ParseFsync(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFsync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if policy != tt.wantPolicy || every != tt.wantEvery {
+				t.Errorf("ParseFsync() = (%q, %d), want (%q, %d)", policy, every, tt.wantPolicy, tt.wantEvery)
+			}
+		})
+	}
+}
+
+func TestHostPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     HostPaths
+		env      map[string]string
+		wantProc string
+		wantSys  string
+	}{
+		{"Local", HostPaths{}, nil, "/proc", "/sys"},
+		{"Host Root", HostPaths{Root: "/host"}, nil, "/host/proc", "/host/sys"},
+		{"Explicit Overrides Root", HostPaths{Proc: "/hproc", Root: "/host"}, nil, "/hproc", "/host/sys"},
+		{"Explicit Only", HostPaths{Proc: "/hproc", Sys: "/hsys"}, nil, "/hproc", "/hsys"},
+		{"Environment", HostPaths{}, map[string]string{"HOST_PROC": "/eproc", "HOST_SYS": "/esys"}, "/eproc", "/esys"},
+		{"Flags Override Environment", HostPaths{Proc: "/hproc", Root: "/host"},
+			map[string]string{"HOST_PROC": "/eproc", "HOST_SYS": "/esys"}, "/hproc", "/host/sys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOST_PROC", tt.env["HOST_PROC"])
+			t.Setenv("HOST_SYS", tt.env["HOST_SYS"])
+			if got := tt.host.ProcRoot(); got != tt.wantProc {
+				t.Errorf("ProcRoot() = %q, want %q", got, tt.wantProc)
+			}
+			if got := tt.host.SysRoot(); got != tt.wantSys {
+				t.Errorf("SysRoot() = %q, want %q", got, tt.wantSys)
+			}
+		})
+	}
+}
+
+func TestHostPaths_Validate(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		host    HostPaths
+		wantErr bool
+	}{
+		{"Unset", HostPaths{}, false},
+		{"Existing Directories", HostPaths{Proc: dir, Sys: dir, Root: dir}, false},
+		{"Relative", HostPaths{Root: "host"}, true},
+		{"Missing", HostPaths{Proc: filepath.Join(dir, "missing")}, true},
+		{"Not A Directory", HostPaths{Sys: file}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.host.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHostPaths_Setenv(t *testing.T) {
+	for _, key := range []string{"HOST_PROC", "HOST_SYS", "HOST_ROOT", "HOST_DEV", "HOST_ETC", "HOST_VAR", "HOST_RUN"} {
+		t.Setenv(key, "unchanged")
+	}
+
+	if err := (HostPaths{Sys: "/hsys"}).Setenv(); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv("HOST_SYS"); got != "/hsys" {
+		t.Errorf("HOST_SYS = %q, want /hsys", got)
+	}
+	if got := os.Getenv("HOST_PROC"); got != "unchanged" {
+		t.Errorf("HOST_PROC = %q, want unset paths left untouched", got)
+	}
+
+	if err := (HostPaths{Root: "/host"}).Setenv(); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"HOST_ROOT": "/host", "HOST_PROC": "/host/proc", "HOST_SYS": "/host/sys", "HOST_DEV": "/host/dev", "HOST_ETC": "/host/etc",
+	} {
+		if got := os.Getenv(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Default locations of the kernel filesystems.
+const (
+	DefaultProcRoot = "/proc"
+	DefaultSysRoot  = "/sys"
+)
+
+// HostPaths locates the filesystems of the monitored host. Running inside a container
+// with the host's /, /proc and /sys mounted (e.g., at /host, /host/proc and /host/sys)
+// monitors the host instead of the container.
+type HostPaths struct {
+	Proc string // Mountpoint of the host procfs (empty = <Root>/proc, else $HOST_PROC, else /proc)
+	Sys  string // Mountpoint of the host sysfs (empty = <Root>/sys, else $HOST_SYS, else /sys)
+	Root string // Mountpoint of the host root filesystem (empty = /)
+}
+
+// ProcRoot returns the procfs mountpoint to read. Without flags, it follows HOST_PROC like gopsutil.
+func (h HostPaths) ProcRoot() string {
+	switch {
+	case h.Proc != "":
+		return h.Proc
+	case h.Root != "":
+		return filepath.Join(h.Root, DefaultProcRoot)
+	default:
+		return envOr("HOST_PROC", DefaultProcRoot)
+	}
+}
+
+// SysRoot returns the sysfs mountpoint to read. Without flags, it follows HOST_SYS like gopsutil.
+func (h HostPaths) SysRoot() string {
+	switch {
+	case h.Sys != "":
+		return h.Sys
+	case h.Root != "":
+		return filepath.Join(h.Root, DefaultSysRoot)
+	default:
+		return envOr("HOST_SYS", DefaultSysRoot)
+	}
+}
+
+// envOr returns the value of an environment variable, or def if it is unset or empty.
+func envOr(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// Validate checks that every configured path is an existing directory.
+func (h HostPaths) Validate() error {
+	for _, p := range []struct{ flag, path string }{
+		{"host-proc", h.Proc}, {"host-sys", h.Sys}, {"host-root", h.Root},
+	} {
+		if p.path == "" {
+			continue
+		}
+		if !filepath.IsAbs(p.path) {
+			return fmt.Errorf("--%s must be an absolute path: %s", p.flag, p.path)
+		}
+		info, err := os.Stat(p.path)
+		if err != nil {
+			return fmt.Errorf("invalid --%s: %w", p.flag, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("invalid --%s: %s is not a directory", p.flag, p.path)
+		}
+	}
+	return nil
+}
+
+// Setenv exports the configured paths as the HOST_* environment variables honored by gopsutil,
+// so the collectors built on it read the host as well. Unconfigured paths are left untouched.
+func (h HostPaths) Setenv() error {
+	vars := make(map[string]string)
+	if h.Root != "" {
+		vars["HOST_ROOT"] = h.Root
+		vars["HOST_DEV"] = filepath.Join(h.Root, "dev")
+		vars["HOST_ETC"] = filepath.Join(h.Root, "etc")
+		vars["HOST_VAR"] = filepath.Join(h.Root, "var")
+		vars["HOST_RUN"] = filepath.Join(h.Root, "run")
+	}
+	if h.Proc != "" || h.Root != "" {
+		vars["HOST_PROC"] = h.ProcRoot()
+	}
+	if h.Sys != "" || h.Root != "" {
+		vars["HOST_SYS"] = h.SysRoot()
+	}
+
+	for key, value := range vars {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ package devices
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -56,7 +57,8 @@ type NetworkInfo struct {
 }
 
 // ListDisks returns a list of available disk devices.
-func ListDisks() ([]DiskInfo, error) {
+// Mountpoints are resolved under rootFS, the mountpoint of the host root filesystem (empty = /).
+func ListDisks(rootFS string) ([]DiskInfo, error) {
 	partitions, err := diskPartitions(false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get disk partitions: %w", err)
@@ -72,7 +74,7 @@ func ListDisks() ([]DiskInfo, error) {
 		}
 		seen[partition.Device] = true
 
-		usage, err := diskUsage(partition.Mountpoint)
+		usage, err := diskUsage(filepath.Join(rootFS, partition.Mountpoint))
 		total := uint64(0)
 		if err == nil {
 			total = usage.Total
@@ -105,7 +105,7 @@ func TestListDisks(t *testing.T) {
 			diskPartitions = tt.mockPartitions
 			diskUsage = tt.mockUsage
 
-			got, err := ListDisks()
+			got, err := ListDisks("")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ListDisks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -26,41 +26,49 @@ package exporter
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
-	"path/filepath"
+	"slices"
 	"sort"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/phuonguno98/unostat/internal/config"
 	"github.com/phuonguno98/unostat/pkg/metrics"
 )
 
+func init() {
+	Register("csv", func(cfg *config.Config, logger *slog.Logger) (Exporter, error) {
+		return NewCSVExporter(cfg, logger)
+	})
+}
+
 // CSVExporter exports metrics to a CSV file with buffering.
 type CSVExporter struct {
 	config        *config.Config
 	file          *os.File
-	csvWriter     *csv.Writer
 	bufWriter     *bufio.Writer
-	metricsChan   <-chan *metrics.Snapshot
-	flushTicker   *time.Ticker
-	recordCount   int
+	rowBuf        bytes.Buffer // Encoded form of the record being written
+	rowWriter     *csv.Writer  // Encodes records into rowBuf so their exact size is known
 	logger        *slog.Logger
 	headerWritten bool
-	deviceOrder   []string       // Track order of devices for consistent columns
-	ifaceOrder    []string       // Track order of interfaces for consistent columns
+	layout        csvLayout      // Column order of the current file
 	location      *time.Location // Timezone location for timestamps
 	currentSize   int64          // Current file size in bytes
 	basePath      string         // Base output path
-	fileIndex     int            // Index for file rotation
+	rotator       *rotator       // Rotation and retention policy
+	existingHdr   []string       // Header found in a non-empty output file on open (nil = new file)
+	unsynced      int            // Records written since the last fsync
+	top           topSidecar     // Top processes of the current file
 }
 
 // NewCSVExporter creates a new CSV exporter instance.
-func NewCSVExporter(cfg *config.Config, metricsChan <-chan *metrics.Snapshot, logger *slog.Logger) (*CSVExporter, error) {
+func NewCSVExporter(cfg *config.Config, logger *slog.Logger) (*CSVExporter, error) {
 	// Parse timezone
 	loc, err := time.LoadLocation(cfg.Timezone)
 	if err != nil {
@@ -70,6 +78,15 @@ func NewCSVExporter(cfg *config.Config, metricsChan <-chan *metrics.Snapshot, lo
 		return nil, fmt.Errorf("invalid timezone '%s': %w", cfg.Timezone, err)
 	}
 
+	// Drop a row left half-written by a crash or power loss before appending to the file
+	removed, err := truncateTornLine(cfg.OutputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover output file: %w", err)
+	}
+	if removed > 0 {
+		logger.Warn("Truncated incomplete last line of existing output file", "output", cfg.OutputPath, "bytes", removed)
+	}
+
 	// Open output file
 	file, err := os.OpenFile(cfg.OutputPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
 	if err != nil {
@@ -79,9 +96,6 @@ func NewCSVExporter(cfg *config.Config, metricsChan <-chan *metrics.Snapshot, lo
 	// Create buffered writer
 	bufWriter := bufio.NewWriterSize(file, 8192) // 8KB buffer
 
-	// Create CSV writer on top of buffered writer
-	csvWriter := csv.NewWriter(bufWriter)
-
 	// Get initial file size (if appending)
 	stat, err := file.Stat()
 	if err != nil {
@@ -89,88 +103,87 @@ func NewCSVExporter(cfg *config.Config, metricsChan <-chan *metrics.Snapshot, lo
 		return nil, fmt.Errorf("failed to stat file: %w", err)
 	}
 
+	// When appending, remember the existing header so it is not written twice
+	var existingHdr []string
+	if stat.Size() > 0 {
+		existingHdr = readCSVHeader(cfg.OutputPath)
+	}
+
 	exporter := &CSVExporter{
 		config:      cfg,
 		file:        file,
-		csvWriter:   csvWriter,
 		bufWriter:   bufWriter,
-		metricsChan: metricsChan,
 		logger:      logger,
 		location:    loc,
 		currentSize: stat.Size(),
 		basePath:    cfg.OutputPath,
-		fileIndex:   0,
+		rotator:     newRotator(cfg, cfg.OutputPath, loc),
+		existingHdr: existingHdr,
+		top:         topSidecar{location: loc},
 	}
+	exporter.rowWriter = csv.NewWriter(&exporter.rowBuf)
+	exporter.rotator.companions = topSidecarCompanions
 
 	return exporter, nil
 }
 
-// Start begins listening to the metrics channel and writing to CSV.
-func (e *CSVExporter) Start(ctx context.Context) error {
-	e.logger.Info("Starting CSV exporter", "output", e.config.OutputPath, "timezone", e.config.Timezone)
-
-	// Start flush ticker
-	e.flushTicker = time.NewTicker(e.config.FlushInterval)
-	defer e.flushTicker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			e.logger.Info("CSV exporter stopping...")
-			return e.flush()
-
-		case snapshot, ok := <-e.metricsChan:
-			if !ok {
-				// Channel closed, flush and exit
-				e.logger.Info("Metrics channel closed, flushing remaining data...")
-				return e.flush()
-			}
-
-			if err := e.writeSnapshot(snapshot); err != nil {
-				e.logger.Error("Failed to write snapshot", "error", err)
-			}
+// Name returns the sink name.
+func (e *CSVExporter) Name() string {
+	return "csv"
+}
 
-			e.recordCount++
+// Start logs the exporter settings; the output file is already opened by NewCSVExporter.
+func (e *CSVExporter) Start(_ context.Context) error {
+	e.logger.Info("Starting CSV exporter", "output", e.config.OutputPath, "timezone", e.config.Timezone)
+	e.rotator.prune(e.file.Name(), e.logger)
+	return nil
+}
 
-			// Flush if buffer size reached
-			if e.recordCount >= e.config.BufferSize {
-				if err := e.flush(); err != nil {
-					e.logger.Error("Failed to flush", "error", err)
-				}
-				e.recordCount = 0
-			}
+// Write appends a snapshot to the CSV buffer.
+func (e *CSVExporter) Write(snapshot *metrics.Snapshot) error {
+	return e.writeSnapshot(snapshot)
+}
 
-		case <-e.flushTicker.C:
-			// Time-based flush
-			if e.recordCount > 0 {
-				if err := e.flush(); err != nil {
-					e.logger.Error("Failed to flush", "error", err)
-				}
-				e.recordCount = 0
-			}
-		}
-	}
+// Flush flushes the buffered data to disk.
+func (e *CSVExporter) Flush() error {
+	return e.flush()
 }
 
 // writeSnapshot writes a single snapshot to the CSV file.
 func (e *CSVExporter) writeSnapshot(snapshot *metrics.Snapshot) error {
 	// Write header if this is the first record
 	if !e.headerWritten {
-		if err := e.writeHeader(snapshot); err != nil {
-			return fmt.Errorf("failed to write header: %w", err)
+		if e.existingHdr != nil {
+			if err := e.resumeExisting(snapshot); err != nil {
+				return err
+			}
+		} else {
+			if err := e.writeHeader(snapshot); err != nil {
+				return fmt.Errorf("failed to write header: %w", err)
+			}
+			e.headerWritten = true
+		}
+	}
+
+	// Roll to a new file when devices or interfaces appear that have no columns yet,
+	// so they are recorded instead of silently dropped. The layout only changes once the
+	// new file has its header; otherwise the new devices are left out (N/A) of this row.
+	extended := e.layout.clone()
+	if added := extended.extend(snapshot); len(added) > 0 {
+		e.logger.Info("New devices detected, rolling to a new file with an extended header", added...)
+		if err := e.rotateFile(snapshot.Timestamp, extended); err != nil {
+			e.logger.Error("Failed to rotate file, keeping the previous columns", "error", err)
 		}
-		e.headerWritten = true
 	}
 
 	// Build row
 	row := e.buildRow(snapshot)
 
-	// Check for rotation if file size exceeds limit
+	// Check for rotation if file size exceeds limit or a wall clock boundary was crossed
 	// We check *before* writing to avoid going too much over the limit
-	// Note: We only rotate if we have written at least something to avoid empty file loops if logic is flawed,
-	// checking currentSize > limit is enough.
-	if e.currentSize >= config.DefaultMaxOutputFileSize {
-		if err := e.rotateFile(snapshot); err != nil {
+	if due, reason := e.rotator.due(e.currentSize, snapshot.Timestamp); due {
+		e.logger.Debug("Rotation due", "reason", reason)
+		if err := e.rotateFile(snapshot.Timestamp, e.layout); err != nil {
 			e.logger.Error("Failed to rotate file", "error", err)
 			// Continue writing to old file if rotation fails?
 			// Ideally we should stop or retry, but logging error and continuing is safer than crashing.
@@ -178,53 +191,359 @@ func (e *CSVExporter) writeSnapshot(snapshot *metrics.Snapshot) error {
 	}
 
 	// Write row
-	rowBytes := 0
-	for _, cell := range row {
-		rowBytes += len(cell) + 1 // +1 for comma
+	if err := e.writeRecord(row); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
 	}
-	rowBytes++ // +1 for newline approximation
 
-	if err := e.csvWriter.Write(row); err != nil {
-		return fmt.Errorf("failed to write row: %w", err)
+	// The ranking is auxiliary data; losing it must not stop the metrics
+	if snapshot.TopProcesses != nil {
+		if err := e.top.write(e.file.Name(), snapshot); err != nil {
+			e.logger.Warn("Failed to write top processes", "error", err)
+		}
+	}
+
+	// Bound the data lost on power failure to FsyncEvery records
+	if e.config.Fsync == config.FsyncEvery {
+		e.unsynced++
+		if e.unsynced >= e.config.FsyncEvery {
+			if err := e.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeRecord encodes a record and appends it to the buffered writer, tracking the exact file size.
+func (e *CSVExporter) writeRecord(record []string) error {
+	e.rowBuf.Reset()
+	if err := e.rowWriter.Write(record); err != nil {
+		return err
+	}
+	e.rowWriter.Flush()
+	if err := e.rowWriter.Error(); err != nil {
+		return err
+	}
+
+	n, err := e.bufWriter.Write(e.rowBuf.Bytes())
+	e.currentSize += int64(n)
+	return err
+}
+
+// truncateTornLine removes a partial last line from a CSV file that does not end with a newline,
+// which is what an interrupted write leaves behind. It returns the number of bytes removed.
+// A missing or empty file is left untouched.
+func truncateTornLine(path string) (int64, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, err
 	}
+	size := stat.Size()
+	if size == 0 {
+		return 0, nil
+	}
+
+	// Scan backwards for the last newline
+	const chunkSize = 4096
+	buf := make([]byte, chunkSize)
+	end := size
+	for end > 0 {
+		start := max(end-chunkSize, 0)
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			end = start + int64(i) + 1
+			break
+		}
+		end = start
+	}
+
+	if end == size {
+		return 0, nil
+	}
+	if err := f.Truncate(end); err != nil {
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, err
+	}
+	return size - end, nil
+}
 
-	e.currentSize += int64(rowBytes) // Approximate size tracking
+// resumeExisting continues an existing file when its header matches the one required
+// by the snapshot. Otherwise it applies the configured schema mismatch policy.
+func (e *CSVExporter) resumeExisting(snapshot *metrics.Snapshot) error {
+	existing := e.existingHdr
+
+	layout := layoutFor(snapshot)
+	header := layout.header()
+	if slices.Equal(header, existing) {
+		e.existingHdr = nil
+		e.layout = layout
+		e.headerWritten = true
+		e.logger.Info("Appending to existing CSV file with matching header", "output", e.file.Name())
+		return nil
+	}
+
+	if e.config.OnSchemaMismatch == config.SchemaMismatchFail {
+		return fmt.Errorf("%w: %w: header of %s has %d columns, current devices require %d (use --on-schema-mismatch=%s or a new output file)",
+			ErrFatal, ErrSchemaMismatch, e.file.Name(), len(existing), len(header), config.SchemaMismatchRotate)
+	}
+
+	e.logger.Warn("Existing CSV header does not match current devices, rotating to a new file",
+		"output", e.file.Name(),
+		"existing_columns", len(existing),
+		"required_columns", len(header),
+	)
+	e.existingHdr = nil
+	if err := e.rotateFile(snapshot.Timestamp, layout); err != nil {
+		return fmt.Errorf("failed to rotate on schema mismatch: %w", err)
+	}
 	return nil
 }
 
-// writeHeader writes the CSV header row.
+// readCSVHeader returns the first record of an existing CSV file.
+// An unreadable header yields an empty, non-nil slice so that it never matches.
+func readCSVHeader(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return []string{}
+	}
+	defer func() { _ = f.Close() }()
+
+	reader := csv.NewReader(bufio.NewReader(f))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return []string{}
+	}
+	return header
+}
+
+// writeHeader writes the CSV header row and fixes the column order.
 func (e *CSVExporter) writeHeader(snapshot *metrics.Snapshot) error {
-	header := []string{"Timestamp", "CPU Utilization (%)", "CPU IO Wait (%)"}
-	header = append(header, "Memory Utilization (%)")
+	e.layout = layoutFor(snapshot)
+	return e.writeRecord(e.layout.header())
+}
+
+// csvLayout is the column order of a CSV file: the names of the entities of every
+// per-entity column group. Within one file columns are never added or removed.
+type csvLayout struct {
+	cores    []string // CPU cores (per-CPU mode)
+	devices  []string // Disk devices
+	ifaces   []string // Network interfaces
+	mounts   []string // Filesystem mountpoints
+	procs    []string // Process selectors
+	cgroups  []string // Cgroups
+	pressure []string // PSI resources
+	system   bool     // Kernel activity counters
+	netstat  bool     // TCP/UDP protocol statistics
+}
+
+// layoutFor returns the column layout of the entities in a snapshot.
+func layoutFor(snapshot *metrics.Snapshot) csvLayout {
+	return csvLayout{
+		cores:    sortedCoreNames(snapshot.Cores),
+		devices:  sortedKeys(snapshot.Disks),
+		ifaces:   sortedKeys(snapshot.Networks),
+		mounts:   sortedKeys(snapshot.Filesystems),
+		procs:    sortedKeys(snapshot.Processes),
+		cgroups:  sortedKeys(snapshot.Cgroups),
+		pressure: sortedKeys(snapshot.Pressure),
+		system:   snapshot.System != nil,
+		netstat:  snapshot.Netstat != nil,
+	}
+}
+
+// extend adds entities of the snapshot that have no columns yet to the layout, keeping
+// every group sorted. It returns the added names as log attributes, or nil if none were added.
+func (l *csvLayout) extend(snapshot *metrics.Snapshot) []any {
+	var added []any
+	merge := func(group string, order *[]string, names []string, sortNames func([]string)) {
+		var fresh []string
+		for _, name := range names {
+			if !slices.Contains(*order, name) {
+				fresh = append(fresh, name)
+			}
+		}
+		if len(fresh) == 0 {
+			return
+		}
+		*order = append(*order, fresh...)
+		sortNames(*order)
+		added = append(added, group, fresh)
+	}
+
+	merge("cores", &l.cores, sortedCoreNames(snapshot.Cores), sortCoreNames)
+	merge("disks", &l.devices, sortedKeys(snapshot.Disks), sort.Strings)
+	merge("networks", &l.ifaces, sortedKeys(snapshot.Networks), sort.Strings)
+	merge("filesystems", &l.mounts, sortedKeys(snapshot.Filesystems), sort.Strings)
+	merge("processes", &l.procs, sortedKeys(snapshot.Processes), sort.Strings)
+	merge("cgroups", &l.cgroups, sortedKeys(snapshot.Cgroups), sort.Strings)
+	merge("pressure", &l.pressure, sortedKeys(snapshot.Pressure), sort.Strings)
+	if snapshot.System != nil && !l.system {
+		l.system = true
+		added = append(added, "system", true)
+	}
+	if snapshot.Netstat != nil && !l.netstat {
+		l.netstat = true
+		added = append(added, "netstat", true)
+	}
+	return added
+}
+
+// clone returns a copy of the layout that can be extended without changing l.
+func (l csvLayout) clone() csvLayout {
+	l.cores = slices.Clone(l.cores)
+	l.devices = slices.Clone(l.devices)
+	l.ifaces = slices.Clone(l.ifaces)
+	l.mounts = slices.Clone(l.mounts)
+	l.procs = slices.Clone(l.procs)
+	l.cgroups = slices.Clone(l.cgroups)
+	l.pressure = slices.Clone(l.pressure)
+	return l
+}
+
+// header builds the CSV header row for the layout.
+func (l *csvLayout) header() []string {
+	header := []string{
+		"Timestamp", "CPU Utilization (%)", "CPU IO Wait (%)",
+		"CPU User (%)", "CPU System (%)", "CPU IRQ (%)", "CPU SoftIRQ (%)", "CPU Steal (%)", "CPU Guest (%)",
+	}
 
-	// Extract and sort device names for consistent ordering
-	e.deviceOrder = make([]string, 0, len(snapshot.Disks))
-	for device := range snapshot.Disks {
-		e.deviceOrder = append(e.deviceOrder, device)
+	// Add per-core columns, led by the busiest core
+	if len(l.cores) > 0 {
+		header = append(header, "CPU Max Core Utilization (%)")
 	}
-	sort.Strings(e.deviceOrder)
+	for _, core := range l.cores {
+		header = append(header,
+			fmt.Sprintf("CPU [%s] Utilization (%%)", core),
+			fmt.Sprintf("CPU [%s] IO Wait (%%)", core))
+	}
+
+	header = append(header,
+		"Memory Utilization (%)",
+		"Memory Available (MB)", "Memory Cached (MB)", "Memory Buffers (MB)",
+		"Memory Dirty (MB)", "Memory Writeback (MB)", "Memory Slab (MB)",
+		"Swap Used (MB)", "Swap Total (MB)", "Swap In (pages/s)", "Swap Out (pages/s)")
 
 	// Add disk columns
-	for _, device := range e.deviceOrder {
+	for _, device := range l.devices {
 		header = append(header,
 			fmt.Sprintf("Disk [%s] Utilization (%%)", device),
 			fmt.Sprintf("Disk [%s] Average Wait (ms)", device),
-			fmt.Sprintf("Disk [%s] Throughput (IOPS)", device))
+			fmt.Sprintf("Disk [%s] Throughput (IOPS)", device),
+			fmt.Sprintf("Disk [%s] Reads (r/s)", device),
+			fmt.Sprintf("Disk [%s] Writes (w/s)", device),
+			fmt.Sprintf("Disk [%s] Read Throughput (kB/s)", device),
+			fmt.Sprintf("Disk [%s] Write Throughput (kB/s)", device),
+			fmt.Sprintf("Disk [%s] Read Wait (ms)", device),
+			fmt.Sprintf("Disk [%s] Write Wait (ms)", device),
+			fmt.Sprintf("Disk [%s] Average Request Size (kB)", device),
+			fmt.Sprintf("Disk [%s] Average Queue Size", device))
 	}
 
-	// Extract and sort interface names for consistent ordering
-	e.ifaceOrder = make([]string, 0, len(snapshot.Networks))
-	for iface := range snapshot.Networks {
-		e.ifaceOrder = append(e.ifaceOrder, iface)
+	// Add network columns
+	for _, iface := range l.ifaces {
+		header = append(header,
+			fmt.Sprintf("Network [%s] Throughput (Mbps)", iface),
+			fmt.Sprintf("Network [%s] Receive (Mbps)", iface),
+			fmt.Sprintf("Network [%s] Transmit (Mbps)", iface),
+			fmt.Sprintf("Network [%s] Receive Packets (pkt/s)", iface),
+			fmt.Sprintf("Network [%s] Transmit Packets (pkt/s)", iface),
+			fmt.Sprintf("Network [%s] Receive Errors (/s)", iface),
+			fmt.Sprintf("Network [%s] Transmit Errors (/s)", iface),
+			fmt.Sprintf("Network [%s] Receive Drops (/s)", iface),
+			fmt.Sprintf("Network [%s] Transmit Drops (/s)", iface))
 	}
-	sort.Strings(e.ifaceOrder)
 
-	// Add network columns
-	for _, iface := range e.ifaceOrder {
-		header = append(header, fmt.Sprintf("Network [%s] Throughput (Mbps)", iface))
+	// Add filesystem columns
+	for _, mount := range l.mounts {
+		header = append(header,
+			fmt.Sprintf("Filesystem [%s] Used (MB)", mount),
+			fmt.Sprintf("Filesystem [%s] Free (MB)", mount),
+			fmt.Sprintf("Filesystem [%s] Used (%%)", mount),
+			fmt.Sprintf("Filesystem [%s] Inodes Used", mount),
+			fmt.Sprintf("Filesystem [%s] Inodes Free", mount),
+			fmt.Sprintf("Filesystem [%s] Inodes Used (%%)", mount))
+	}
+
+	// Add process columns
+	for _, proc := range l.procs {
+		header = append(header,
+			fmt.Sprintf("Process [%s] Count", proc),
+			fmt.Sprintf("Process [%s] CPU (%%)", proc),
+			fmt.Sprintf("Process [%s] RSS (MB)", proc),
+			fmt.Sprintf("Process [%s] Threads", proc),
+			fmt.Sprintf("Process [%s] Open FDs", proc),
+			fmt.Sprintf("Process [%s] Read (kB/s)", proc),
+			fmt.Sprintf("Process [%s] Write (kB/s)", proc),
+			fmt.Sprintf("Process [%s] Context Switches (/s)", proc))
+	}
+
+	// Add cgroup columns, with I/O summed over all devices
+	for _, cg := range l.cgroups {
+		header = append(header,
+			fmt.Sprintf("Cgroup [%s] CPU (%%)", cg),
+			fmt.Sprintf("Cgroup [%s] CPU User (%%)", cg),
+			fmt.Sprintf("Cgroup [%s] CPU System (%%)", cg),
+			fmt.Sprintf("Cgroup [%s] Throttled Periods (%%)", cg),
+			fmt.Sprintf("Cgroup [%s] Throttled Time (ms/s)", cg),
+			fmt.Sprintf("Cgroup [%s] Memory (MB)", cg),
+			fmt.Sprintf("Cgroup [%s] Memory Limit (MB)", cg),
+			fmt.Sprintf("Cgroup [%s] Memory Used (%%)", cg),
+			fmt.Sprintf("Cgroup [%s] PIDs", cg),
+			fmt.Sprintf("Cgroup [%s] Read (kB/s)", cg),
+			fmt.Sprintf("Cgroup [%s] Write (kB/s)", cg),
+			fmt.Sprintf("Cgroup [%s] Read IOPS", cg),
+			fmt.Sprintf("Cgroup [%s] Write IOPS", cg))
+	}
+
+	// Add pressure stall columns
+	for _, resource := range l.pressure {
+		header = append(header,
+			fmt.Sprintf("Pressure [%s] Some (%%)", resource),
+			fmt.Sprintf("Pressure [%s] Full (%%)", resource),
+			fmt.Sprintf("Pressure [%s] Some Avg10 (%%)", resource),
+			fmt.Sprintf("Pressure [%s] Some Avg60 (%%)", resource),
+			fmt.Sprintf("Pressure [%s] Full Avg10 (%%)", resource),
+			fmt.Sprintf("Pressure [%s] Full Avg60 (%%)", resource))
 	}
 
-	return e.csvWriter.Write(header)
+	// Add kernel activity columns
+	if l.system {
+		header = append(header,
+			"System Load 1m", "System Load 5m", "System Load 15m",
+			"System Context Switches (/s)", "System Interrupts (/s)", "System Forks (/s)",
+			"System Procs Running", "System Procs Blocked")
+	}
+
+	// Add protocol statistics columns, led by the connections in every TCP state
+	if l.netstat {
+		for _, state := range metrics.TCPStates {
+			header = append(header, fmt.Sprintf("TCP [%s] Connections", state))
+		}
+		header = append(header,
+			"TCP Active Opens (/s)", "TCP Passive Opens (/s)", "TCP Retransmits (/s)",
+			"TCP Listen Overflows (/s)", "TCP Listen Drops (/s)",
+			"UDP Receive Errors (/s)", "UDP Receive Buffer Errors (/s)")
+	}
+
+	// Number of devices whose counters were reset (their columns are N/A)
+	header = append(header, "Counter Resets")
+
+	return header
 }
 
 // buildRow builds a CSV row from a snapshot.
@@ -236,37 +555,241 @@ func (e *CSVExporter) buildRow(snapshot *metrics.Snapshot) []string {
 		ts.Format("2006-01-02 15:04:05"),
 		fmt.Sprintf("%.2f", snapshot.CPU),
 		e.formatCPUWait(snapshot.CPUWait),
-		fmt.Sprintf("%.2f", snapshot.Memory),
+		fmt.Sprintf("%.2f", snapshot.CPUTimes.User),
+		fmt.Sprintf("%.2f", snapshot.CPUTimes.System),
+		fmt.Sprintf("%.2f", snapshot.CPUTimes.Irq),
+		fmt.Sprintf("%.2f", snapshot.CPUTimes.SoftIrq),
+		fmt.Sprintf("%.2f", snapshot.CPUTimes.Steal),
+		fmt.Sprintf("%.2f", snapshot.CPUTimes.Guest),
 	}
 
+	// Add per-core metrics in consistent order
+	if len(e.layout.cores) > 0 {
+		if _, maxUtil, ok := snapshot.MaxCore(); ok {
+			row = append(row, fmt.Sprintf("%.2f", maxUtil))
+		} else {
+			row = append(row, naString)
+		}
+	}
+	for _, core := range e.layout.cores {
+		if stats, ok := snapshot.Cores[core]; ok {
+			row = append(row, fmt.Sprintf("%.2f", stats.Utilization), e.formatCPUWait(stats.IOWait))
+		} else {
+			row = append(row, naString, naString)
+		}
+	}
+
+	mem := snapshot.MemDetail
+	row = append(row,
+		fmt.Sprintf("%.2f", snapshot.Memory),
+		formatMB(mem.Available), formatMB(mem.Cached), formatMB(mem.Buffers),
+		formatMB(mem.Dirty), formatMB(mem.WriteBack), formatMB(mem.Slab),
+		formatMB(mem.SwapUsed), formatMB(mem.SwapTotal),
+		fmt.Sprintf("%.2f", mem.SwapIn), fmt.Sprintf("%.2f", mem.SwapOut))
+
 	// Add disk metrics in consistent order
-	for _, device := range e.deviceOrder {
+	for _, device := range e.layout.devices {
 		if stats, ok := snapshot.Disks[device]; ok {
 			row = append(row,
 				fmt.Sprintf("%.2f", stats.Utilization),
 				fmt.Sprintf("%.2f", stats.Await),
-				fmt.Sprintf("%.2f", stats.IOPS))
+				fmt.Sprintf("%.2f", stats.IOPS),
+				fmt.Sprintf("%.2f", stats.ReadIOPS),
+				fmt.Sprintf("%.2f", stats.WriteIOPS),
+				fmt.Sprintf("%.2f", stats.ReadThroughput/1024),
+				fmt.Sprintf("%.2f", stats.WriteThroughput/1024),
+				fmt.Sprintf("%.2f", stats.ReadAwait),
+				fmt.Sprintf("%.2f", stats.WriteAwait),
+				fmt.Sprintf("%.2f", stats.AvgRequestSize/1024),
+				fmt.Sprintf("%.2f", stats.QueueDepth))
 		} else {
-			row = append(row, naString, naString, naString)
+			row = append(row, slices.Repeat([]string{naString}, diskColumns)...)
 		}
 	}
 
 	// Add network metrics in consistent order
-	for _, iface := range e.ifaceOrder {
+	for _, iface := range e.layout.ifaces {
 		if stats, ok := snapshot.Networks[iface]; ok {
 			// Convert bits per second to Mbps
-			mbps := stats.Bandwidth / 1_000_000
-			row = append(row, fmt.Sprintf("%.2f", mbps))
+			row = append(row,
+				fmt.Sprintf("%.2f", stats.Bandwidth/1_000_000),
+				fmt.Sprintf("%.2f", stats.RxBandwidth/1_000_000),
+				fmt.Sprintf("%.2f", stats.TxBandwidth/1_000_000),
+				fmt.Sprintf("%.2f", stats.RxPackets),
+				fmt.Sprintf("%.2f", stats.TxPackets),
+				fmt.Sprintf("%.2f", stats.ErrIn),
+				fmt.Sprintf("%.2f", stats.ErrOut),
+				fmt.Sprintf("%.2f", stats.DropIn),
+				fmt.Sprintf("%.2f", stats.DropOut))
 		} else {
-			row = append(row, naString)
+			row = append(row, slices.Repeat([]string{naString}, networkColumns)...)
+		}
+	}
+
+	// Add filesystem metrics in consistent order
+	for _, mount := range e.layout.mounts {
+		if stats, ok := snapshot.Filesystems[mount]; ok {
+			row = append(row,
+				formatMB(stats.Used),
+				formatMB(stats.Free),
+				fmt.Sprintf("%.2f", stats.UsedPercent),
+				strconv.FormatUint(stats.InodesUsed, 10),
+				strconv.FormatUint(stats.InodesFree, 10),
+				fmt.Sprintf("%.2f", stats.InodesUsedPercent))
+		} else {
+			row = append(row, slices.Repeat([]string{naString}, filesystemColumns)...)
+		}
+	}
+
+	// Add process metrics in consistent order
+	for _, proc := range e.layout.procs {
+		if stats, ok := snapshot.Processes[proc]; ok {
+			row = append(row,
+				strconv.Itoa(stats.Count),
+				fmt.Sprintf("%.2f", stats.CPU),
+				formatMB(stats.RSS),
+				strconv.FormatInt(stats.Threads, 10),
+				strconv.FormatInt(stats.FDs, 10),
+				fmt.Sprintf("%.2f", stats.ReadBytes/1024),
+				fmt.Sprintf("%.2f", stats.WriteBytes/1024),
+				fmt.Sprintf("%.2f", stats.CtxSwitches))
+		} else {
+			row = append(row, slices.Repeat([]string{naString}, processColumns)...)
 		}
 	}
 
+	// Add cgroup metrics in consistent order
+	for _, cg := range e.layout.cgroups {
+		stats, ok := snapshot.Cgroups[cg]
+		if !ok {
+			row = append(row, slices.Repeat([]string{naString}, cgroupColumns)...)
+			continue
+		}
+
+		// Memory limit and utilization are N/A for unlimited cgroups
+		memLimit, memPercent := naString, naString
+		if stats.MemoryMax > 0 {
+			memLimit = formatMB(stats.MemoryMax)
+			memPercent = fmt.Sprintf("%.2f", stats.MemoryPercent)
+		}
+		var total metrics.CgroupIOStats
+		for _, dev := range stats.IO {
+			total.ReadBytes += dev.ReadBytes
+			total.WriteBytes += dev.WriteBytes
+			total.ReadIOPS += dev.ReadIOPS
+			total.WriteIOPS += dev.WriteIOPS
+		}
+		row = append(row,
+			fmt.Sprintf("%.2f", stats.CPU),
+			fmt.Sprintf("%.2f", stats.CPUUser),
+			fmt.Sprintf("%.2f", stats.CPUSystem),
+			fmt.Sprintf("%.2f", stats.ThrottledPercent),
+			fmt.Sprintf("%.2f", stats.ThrottledTime),
+			formatMB(stats.MemoryCurrent),
+			memLimit,
+			memPercent,
+			strconv.FormatUint(stats.PIDs, 10),
+			fmt.Sprintf("%.2f", total.ReadBytes/1024),
+			fmt.Sprintf("%.2f", total.WriteBytes/1024),
+			fmt.Sprintf("%.2f", total.ReadIOPS),
+			fmt.Sprintf("%.2f", total.WriteIOPS))
+	}
+
+	// Add pressure stall metrics in consistent order; full stalls are N/A if not reported
+	for _, resource := range e.layout.pressure {
+		if stats, ok := snapshot.Pressure[resource]; ok {
+			row = append(row,
+				fmt.Sprintf("%.2f", stats.Some),
+				formatOptionalPercent(stats.Full),
+				fmt.Sprintf("%.2f", stats.SomeAvg10),
+				fmt.Sprintf("%.2f", stats.SomeAvg60),
+				formatOptionalPercent(stats.FullAvg10),
+				formatOptionalPercent(stats.FullAvg60))
+		} else {
+			row = append(row, slices.Repeat([]string{naString}, pressureColumns)...)
+		}
+	}
+
+	// Add kernel activity metrics
+	if e.layout.system {
+		if stats := snapshot.System; stats != nil {
+			row = append(row,
+				fmt.Sprintf("%.2f", stats.Load1),
+				fmt.Sprintf("%.2f", stats.Load5),
+				fmt.Sprintf("%.2f", stats.Load15),
+				fmt.Sprintf("%.2f", stats.ContextSwitches),
+				fmt.Sprintf("%.2f", stats.Interrupts),
+				fmt.Sprintf("%.2f", stats.Forks),
+				strconv.FormatUint(stats.ProcsRunning, 10),
+				strconv.FormatUint(stats.ProcsBlocked, 10))
+		} else {
+			row = append(row, slices.Repeat([]string{naString}, systemColumns)...)
+		}
+	}
+
+	// Add protocol statistics
+	if e.layout.netstat {
+		if stats := snapshot.Netstat; stats != nil {
+			for _, state := range metrics.TCPStates {
+				row = append(row, strconv.FormatUint(stats.TCPStates[state], 10))
+			}
+			row = append(row,
+				fmt.Sprintf("%.2f", stats.ActiveOpens),
+				fmt.Sprintf("%.2f", stats.PassiveOpens),
+				fmt.Sprintf("%.2f", stats.RetransSegs),
+				fmt.Sprintf("%.2f", stats.ListenOverflows),
+				fmt.Sprintf("%.2f", stats.ListenDrops),
+				fmt.Sprintf("%.2f", stats.UDPInErrors),
+				fmt.Sprintf("%.2f", stats.UDPRcvbufErrors))
+		} else {
+			row = append(row, slices.Repeat([]string{naString}, len(metrics.TCPStates)+netstatRateColumns)...)
+		}
+	}
+
+	row = append(row, strconv.Itoa(len(snapshot.CounterResets)))
+
 	return row
 }
 
 const naString = "N/A"
 
+// diskColumns is the number of columns per disk device.
+const diskColumns = 11
+
+// networkColumns is the number of columns per network interface.
+const networkColumns = 9
+
+// filesystemColumns is the number of columns per filesystem mountpoint.
+const filesystemColumns = 6
+
+// processColumns is the number of columns per process selector.
+const processColumns = 8
+
+// cgroupColumns is the number of columns per cgroup.
+const cgroupColumns = 13
+
+// pressureColumns is the number of columns per PSI resource.
+const pressureColumns = 6
+
+// systemColumns is the number of kernel activity columns.
+const systemColumns = 8
+
+// netstatRateColumns is the number of protocol rate columns, following one column per TCP state.
+const netstatRateColumns = 7
+
+// formatOptionalPercent formats a percentage, or N/A if it is negative (not available).
+func formatOptionalPercent(value float64) string {
+	if value < 0 {
+		return naString
+	}
+	return fmt.Sprintf("%.2f", value)
+}
+
+// formatMB formats a byte count in megabytes (1MB = 1024*1024 bytes).
+func formatMB(bytes uint64) string {
+	return fmt.Sprintf("%.2f", float64(bytes)/(1024*1024))
+}
+
 // formatCPUWait formats CPU wait value, handling N/A case.
 func (e *CSVExporter) formatCPUWait(cpuWait float64) string {
 	if cpuWait < 0 {
@@ -275,18 +798,26 @@ func (e *CSVExporter) formatCPUWait(cpuWait float64) string {
 	return fmt.Sprintf("%.2f", cpuWait)
 }
 
-// flush flushes the buffered data to disk.
+// flush flushes the buffered data to disk, syncing the file when the fsync policy asks for it.
 func (e *CSVExporter) flush() error {
-	e.csvWriter.Flush()
-	if err := e.csvWriter.Error(); err != nil {
-		return fmt.Errorf("CSV writer error: %w", err)
-	}
-
 	if err := e.bufWriter.Flush(); err != nil {
 		return fmt.Errorf("buffer writer error: %w", err)
 	}
+	if err := e.top.flush(); err != nil {
+		e.logger.Warn("Failed to flush top processes", "error", err)
+	}
+
+	if e.config.Fsync == config.FsyncOnFlush || (e.config.Fsync == config.FsyncEvery && e.unsynced > 0) {
+		if err := e.file.Sync(); err != nil {
+			return fmt.Errorf("fsync error: %w", err)
+		}
+		if err := e.top.sync(); err != nil {
+			e.logger.Warn("Failed to fsync top processes", "error", err)
+		}
+		e.unsynced = 0
+	}
 
-	e.logger.Debug("Flushed to disk", "records", e.recordCount)
+	e.logger.Debug("Flushed to disk", "output", e.file.Name())
 	return nil
 }
 
@@ -294,27 +825,31 @@ func (e *CSVExporter) flush() error {
 func (e *CSVExporter) Close() error {
 	e.logger.Info("Closing CSV exporter")
 
-	if e.flushTicker != nil {
-		e.flushTicker.Stop()
-	}
-
 	// Final flush
 	if err := e.flush(); err != nil {
 		e.logger.Error("Final flush failed", "error", err)
 	}
 
+	if _, err := e.top.close(); err != nil {
+		e.logger.Warn("Failed to close top processes file", "error", err)
+	}
+
 	// Close file
 	if err := e.file.Close(); err != nil {
 		return fmt.Errorf("failed to close file: %w", err)
 	}
 
+	// Wait for background compression of rotated files
+	e.rotator.wait()
+
 	e.logger.Info("CSV exporter closed")
 	return nil
 }
 
-// rotateFile rotates the output file.
-// rotateFile rotates the output file.
-func (e *CSVExporter) rotateFile(snapshot *metrics.Snapshot) error {
+// rotateFile rotates the output file and applies the retention policy.
+// The new file starts with the header of layout, which becomes the current column order
+// on success; start is the timestamp of its first record.
+func (e *CSVExporter) rotateFile(start time.Time, layout csvLayout) error {
 	e.logger.Info("Rotating output file", "current_size", e.currentSize)
 
 	// Flush and close current file
@@ -324,22 +859,17 @@ func (e *CSVExporter) rotateFile(snapshot *metrics.Snapshot) error {
 	if err := e.file.Close(); err != nil {
 		return fmt.Errorf("close before rotate failed: %w", err)
 	}
+	e.rotator.compressClosed(e.file.Name(), e.logger)
 
-	// Generate new filename with collision existence check
-	ext := filepath.Ext(e.basePath)
-	base := strings.TrimSuffix(e.basePath, ext)
-	var newPath string
-
-	for {
-		e.fileIndex++
-		newPath = fmt.Sprintf("%s_%d%s", base, e.fileIndex, ext)
-		// Check if file exists to avoid overwriting previous run data or manual files
-		if _, err := os.Stat(newPath); os.IsNotExist(err) {
-			break
-		}
-		// If exists, loop will continue and increment index
+	// The next ranking opens the sidecar of the new file
+	if topPath, err := e.top.close(); err != nil {
+		e.logger.Warn("Failed to close top processes file", "error", err)
+	} else if topPath != "" {
+		e.rotator.compressClosed(topPath, e.logger)
 	}
 
+	newPath := e.rotator.nextPath(start)
+
 	// Open new file
 	file, err := os.OpenFile(newPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
 	if err != nil {
@@ -349,16 +879,17 @@ func (e *CSVExporter) rotateFile(snapshot *metrics.Snapshot) error {
 	// Update exporter state
 	e.file = file
 	e.bufWriter = bufio.NewWriterSize(file, 8192)
-	e.csvWriter = csv.NewWriter(e.bufWriter)
 	e.currentSize = 0
 	e.headerWritten = false
 
 	// Write header to new file immediately
-	if err := e.writeHeader(snapshot); err != nil {
+	if err := e.writeRecord(layout.header()); err != nil {
 		return fmt.Errorf("failed to write header to rotated file: %w", err)
 	}
+	e.layout = layout
 	e.headerWritten = true
 
 	e.logger.Info("File rotated successfully", "new_path", newPath)
+	e.rotator.prune(newPath, e.logger)
 	return nil
 }
@@ -25,11 +25,17 @@
 package exporter
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -61,7 +67,7 @@ func TestCSVExporter_Export(t *testing.T) {
 		SamplingInterval: 1 * time.Second,
 	}
 
-	exporter, err := NewCSVExporter(cfg, metricsChan, logger)
+	exporter, err := NewCSVExporter(cfg, logger)
 	if err != nil {
 		t.Fatalf("NewCSVExporter() error = %v", err)
 	}
@@ -69,8 +75,9 @@ func TestCSVExporter_Export(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan error)
 
+	dispatcher := NewDispatcher(cfg, metricsChan, []Exporter{exporter}, logger)
 	go func() {
-		done <- exporter.Start(ctx)
+		done <- dispatcher.Start(ctx)
 	}()
 
 	// Create a snapshot
@@ -79,13 +86,26 @@ func TestCSVExporter_Export(t *testing.T) {
 		Timestamp: now,
 		CPU:       45.5,
 		CPUWait:   2.5,
+		CPUTimes:  metrics.CPUBreakdown{User: 30, System: 10, Irq: 0.5, SoftIrq: 1, Steal: 4, Guest: 2},
 		Memory:    60.0,
+		MemDetail: metrics.MemoryStats{
+			Available: 2048 << 20, Cached: 512 << 20, Buffers: 64 << 20, Dirty: 1 << 20, WriteBack: 0, Slab: 128 << 20,
+			SwapUsed: 256 << 20, SwapTotal: 1024 << 20, SwapIn: 1.5, SwapOut: 12.25,
+		},
 		Disks: map[string]metrics.DiskStats{
-			"sda": {Utilization: 10.5, Await: 5.0, IOPS: 100.0},
+			"sda": {
+				Utilization: 10.5, Await: 5.0, IOPS: 100.0,
+				ReadIOPS: 40.0, WriteIOPS: 60.0, ReadThroughput: 4096, WriteThroughput: 10240,
+				ReadAwait: 2.0, WriteAwait: 7.0, AvgRequestSize: 143.36, QueueDepth: 0.75,
+			},
 		},
 		Networks: map[string]metrics.NetStats{
-			"eth0": {Bandwidth: 10_000_000}, // 10 Mbps
+			"eth0": {
+				Bandwidth: 10_000_000, RxBandwidth: 8_000_000, TxBandwidth: 2_000_000, // 10 Mbps
+				RxPackets: 900, TxPackets: 300, ErrIn: 1, ErrOut: 0, DropIn: 2.5, DropOut: 0,
+			},
 		},
+		CounterResets: []string{"disk/sdb"},
 	}
 
 	metricsChan <- snapshot
@@ -129,11 +149,44 @@ func TestCSVExporter_Export(t *testing.T) {
 		"Timestamp",
 		"CPU Utilization (%)",
 		"CPU IO Wait (%)",
+		"CPU User (%)",
+		"CPU System (%)",
+		"CPU IRQ (%)",
+		"CPU SoftIRQ (%)",
+		"CPU Steal (%)",
+		"CPU Guest (%)",
 		"Memory Utilization (%)",
+		"Memory Available (MB)",
+		"Memory Cached (MB)",
+		"Memory Buffers (MB)",
+		"Memory Dirty (MB)",
+		"Memory Writeback (MB)",
+		"Memory Slab (MB)",
+		"Swap Used (MB)",
+		"Swap Total (MB)",
+		"Swap In (pages/s)",
+		"Swap Out (pages/s)",
 		"Disk [sda] Utilization (%)",
 		"Disk [sda] Average Wait (ms)",
 		"Disk [sda] Throughput (IOPS)",
+		"Disk [sda] Reads (r/s)",
+		"Disk [sda] Writes (w/s)",
+		"Disk [sda] Read Throughput (kB/s)",
+		"Disk [sda] Write Throughput (kB/s)",
+		"Disk [sda] Read Wait (ms)",
+		"Disk [sda] Write Wait (ms)",
+		"Disk [sda] Average Request Size (kB)",
+		"Disk [sda] Average Queue Size",
 		"Network [eth0] Throughput (Mbps)",
+		"Network [eth0] Receive (Mbps)",
+		"Network [eth0] Transmit (Mbps)",
+		"Network [eth0] Receive Packets (pkt/s)",
+		"Network [eth0] Transmit Packets (pkt/s)",
+		"Network [eth0] Receive Errors (/s)",
+		"Network [eth0] Transmit Errors (/s)",
+		"Network [eth0] Receive Drops (/s)",
+		"Network [eth0] Transmit Drops (/s)",
+		"Counter Resets",
 	}
 
 	header := records[0]
@@ -148,16 +201,49 @@ func TestCSVExporter_Export(t *testing.T) {
 
 	// Check Data Row
 	row := records[1]
-	// Timestamp 2023-10-26 12:00:00, CPU 45.50, Wait 2.50, Mem 60.00, Disk 10.50, Wait 5.00, IOPS 100.00, Net 10.00
+	// Timestamp 2023-10-26 12:00:00, CPU 45.50, Wait 2.50, Breakdown, Mem 60.00, Memory breakdown, Disk 10.50, Wait 5.00, IOPS 100.00, Read/write split, Net 10.00, Rx/Tx split, Counter Resets 1
 	expectedRow := []string{
 		"2023-10-26 12:00:00",
 		"45.50",
 		"2.50",
+		"30.00",
+		"10.00",
+		"0.50",
+		"1.00",
+		"4.00",
+		"2.00",
 		"60.00",
+		"2048.00",
+		"512.00",
+		"64.00",
+		"1.00",
+		"0.00",
+		"128.00",
+		"256.00",
+		"1024.00",
+		"1.50",
+		"12.25",
 		"10.50",
 		"5.00",
 		"100.00",
+		"40.00",
+		"60.00",
+		"4.00",
 		"10.00",
+		"2.00",
+		"7.00",
+		"0.14",
+		"0.75",
+		"10.00",
+		"8.00",
+		"2.00",
+		"900.00",
+		"300.00",
+		"1.00",
+		"0.00",
+		"2.50",
+		"0.00",
+		"1",
 	}
 
 	for i, v := range row {
@@ -191,7 +277,7 @@ func TestCSVExporter_NA_Handling(t *testing.T) {
 		SamplingInterval: 1 * time.Second,
 	}
 
-	exporter, err := NewCSVExporter(cfg, metricsChan, logger)
+	exporter, err := NewCSVExporter(cfg, logger)
 	if err != nil {
 		t.Fatalf("NewCSVExporter() error = %v", err)
 	}
@@ -199,8 +285,9 @@ func TestCSVExporter_NA_Handling(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan error)
 
+	dispatcher := NewDispatcher(cfg, metricsChan, []Exporter{exporter}, logger)
 	go func() {
-		done <- exporter.Start(ctx)
+		done <- dispatcher.Start(ctx)
 	}()
 
 	// Snapshot 1: Defines structure (sda)
@@ -249,19 +336,14 @@ func TestCSVExporter_NA_Handling(t *testing.T) {
 	}
 
 	// Check 2nd row (Snapshot 2) for N/A
-	row2 := records[2]
-	// CPUWait column index is 2
-	if row2[2] != naString {
-		t.Errorf("Expected CPUWait to be N/A, got %q", row2[2])
+	header, row2 := records[0], records[2]
+	if got := row2[csvColumn(t, header, "CPU IO Wait (%)")]; got != naString {
+		t.Errorf("Expected CPUWait to be N/A, got %q", got)
 	}
-	// Disk columns should be at end.
-	// Header: Time, CPU, Wait, Mem, DiskUtil, DiskWait
-	// Indexes: 0, 1, 2, 3, 4, 5
-	if len(row2) < 6 {
-		t.Fatalf("Row 2 too short")
-	}
-	if row2[4] != naString || row2[5] != naString {
-		t.Errorf("Expected Disk stats to be N/A, got %q, %q", row2[4], row2[5])
+	diskUtil := row2[csvColumn(t, header, "Disk [sda] Utilization (%)")]
+	diskWait := row2[csvColumn(t, header, "Disk [sda] Average Wait (ms)")]
+	if diskUtil != naString || diskWait != naString {
+		t.Errorf("Expected Disk stats to be N/A, got %q, %q", diskUtil, diskWait)
 	}
 }
 
@@ -289,7 +371,7 @@ func TestCSVExporter_FileRotation(t *testing.T) {
 		SamplingInterval: 1 * time.Second,
 	}
 
-	exporter, err := NewCSVExporter(cfg, metricsChan, logger)
+	exporter, err := NewCSVExporter(cfg, logger)
 	if err != nil {
 		t.Fatalf("NewCSVExporter() error = %v", err)
 	}
@@ -300,8 +382,9 @@ func TestCSVExporter_FileRotation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan error)
 
+	dispatcher := NewDispatcher(cfg, metricsChan, []Exporter{exporter}, logger)
 	go func() {
-		done <- exporter.Start(ctx)
+		done <- dispatcher.Start(ctx)
 	}()
 
 	// Send snapshot to trigger rotation
@@ -377,7 +460,7 @@ func TestCSVExporter_FileRotation_NoOverwrite(t *testing.T) {
 		SamplingInterval: 1 * time.Second,
 	}
 
-	exporter, err := NewCSVExporter(cfg, metricsChan, logger)
+	exporter, err := NewCSVExporter(cfg, logger)
 	if err != nil {
 		t.Fatalf("NewCSVExporter() error = %v", err)
 	}
@@ -388,8 +471,9 @@ func TestCSVExporter_FileRotation_NoOverwrite(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan error)
 
+	dispatcher := NewDispatcher(cfg, metricsChan, []Exporter{exporter}, logger)
 	go func() {
-		done <- exporter.Start(ctx)
+		done <- dispatcher.Start(ctx)
 	}()
 
 	// Send snapshot to trigger rotation
@@ -434,7 +518,6 @@ func TestCSVExporter_InvalidTimezone(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tempDir) }()
 
 	outputPath := filepath.Join(tempDir, "test.csv")
-	metricsChan := make(chan *metrics.Snapshot, 10)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	cfg := &config.Config{
@@ -445,8 +528,676 @@ func TestCSVExporter_InvalidTimezone(t *testing.T) {
 		SamplingInterval: 1 * time.Second,
 	}
 
-	_, err = NewCSVExporter(cfg, metricsChan, logger)
+	_, err = NewCSVExporter(cfg, logger)
 	if err == nil {
 		t.Error("Expected error for invalid timezone, got nil")
 	}
 }
+
+// writeCSVRun writes snapshots with a fresh exporter, as a separate `collect` run would.
+func writeCSVRun(t *testing.T, cfg *config.Config, snapshots ...*metrics.Snapshot) error {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exp, err := NewCSVExporter(cfg, logger)
+	if err != nil {
+		t.Fatalf("NewCSVExporter() error = %v", err)
+	}
+	defer func() {
+		if err := exp.Close(); err != nil {
+			t.Errorf("Failed to close exporter: %v", err)
+		}
+	}()
+	if err := exp.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	for _, s := range snapshots {
+		if err := exp.Write(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readCSVRecords reads every record of a CSV file.
+func readCSVRecords(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	return records
+}
+
+// csvColumn returns the index of a named column in a CSV header.
+func csvColumn(t *testing.T, header []string, name string) int {
+	t.Helper()
+	i := slices.Index(header, name)
+	if i < 0 {
+		t.Fatalf("Column %q not found in header %v", name, header)
+	}
+	return i
+}
+
+func TestCSVExporter_AppendMatchingHeader(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "append.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+	snapshot := &metrics.Snapshot{
+		Timestamp: time.Now(),
+		Disks:     map[string]metrics.DiskStats{"sda": {}},
+		Networks:  map[string]metrics.NetStats{"eth0": {}},
+	}
+
+	if err := writeCSVRun(t, cfg, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeCSVRun(t, cfg, snapshot, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readCSVRecords(t, outputPath)
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records (Header + 3 Rows), got %d", len(records))
+	}
+	for i, rec := range records[1:] {
+		if rec[0] == "Timestamp" {
+			t.Errorf("Duplicate header found at record %d", i+1)
+		}
+	}
+}
+
+func TestCSVExporter_AppendSchemaMismatch(t *testing.T) {
+	first := &metrics.Snapshot{
+		Timestamp: time.Now(),
+		Disks:     map[string]metrics.DiskStats{"sda": {}},
+	}
+	second := &metrics.Snapshot{
+		Timestamp: time.Now(),
+		Disks:     map[string]metrics.DiskStats{"sda": {}, "sdb": {}},
+	}
+
+	t.Run("Rotate", func(t *testing.T) {
+		tempDir := t.TempDir()
+		outputPath := filepath.Join(tempDir, "mismatch.csv")
+		cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC", OnSchemaMismatch: config.SchemaMismatchRotate}
+
+		if err := writeCSVRun(t, cfg, first); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeCSVRun(t, cfg, second); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		if got := len(readCSVRecords(t, outputPath)); got != 2 {
+			t.Errorf("Original file has %d records, want 2 (untouched)", got)
+		}
+		rotated := readCSVRecords(t, filepath.Join(tempDir, "mismatch_1.csv"))
+		if len(rotated) != 2 || !slices.Contains(rotated[0], "Disk [sdb] Utilization (%)") {
+			t.Errorf("Rotated file should have the extended header and one row, got %v", rotated)
+		}
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		tempDir := t.TempDir()
+		outputPath := filepath.Join(tempDir, "mismatch.csv")
+		cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC", OnSchemaMismatch: config.SchemaMismatchFail}
+
+		if err := writeCSVRun(t, cfg, first); err != nil {
+			t.Fatal(err)
+		}
+		err := writeCSVRun(t, cfg, second)
+		if !errors.Is(err, ErrSchemaMismatch) || !errors.Is(err, ErrFatal) {
+			t.Fatalf("Write() error = %v, want fatal schema mismatch", err)
+		}
+
+		if got := len(readCSVRecords(t, outputPath)); got != 2 {
+			t.Errorf("Original file has %d records, want 2 (untouched)", got)
+		}
+	})
+}
+
+func TestCSVExporter_DynamicSchema(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "dynamic.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+
+	snapshots := []*metrics.Snapshot{
+		{Timestamp: time.Now(), Disks: map[string]metrics.DiskStats{"sda": {IOPS: 1}}},
+		// A USB disk and a new interface appear mid-run
+		{
+			Timestamp: time.Now(),
+			Disks:     map[string]metrics.DiskStats{"sda": {IOPS: 2}, "sdb": {IOPS: 3}},
+			Networks:  map[string]metrics.NetStats{"veth0": {Bandwidth: 1_000_000}},
+		},
+		// sda and veth0 vanish: their columns carry N/A, no further roll
+		{Timestamp: time.Now(), Disks: map[string]metrics.DiskStats{"sdb": {IOPS: 4}}},
+	}
+	if err := writeCSVRun(t, cfg, snapshots...); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(readCSVRecords(t, outputPath)); got != 2 {
+		t.Errorf("First segment has %d records, want 2", got)
+	}
+
+	records := readCSVRecords(t, filepath.Join(tempDir, "dynamic_1.csv"))
+	if len(records) != 3 {
+		t.Fatalf("Second segment has %d records, want 3", len(records))
+	}
+
+	// Devices keep sorted column order: sda, sdb, then the new interface
+	header := records[0]
+	columnOrder := []string{"Disk [sda] Utilization (%)", "Disk [sdb] Utilization (%)", "Network [veth0] Throughput (Mbps)"}
+	for i := 1; i < len(columnOrder); i++ {
+		if csvColumn(t, header, columnOrder[i-1]) > csvColumn(t, header, columnOrder[i]) {
+			t.Errorf("Column %q should come before %q", columnOrder[i-1], columnOrder[i])
+		}
+	}
+	sdaIOPS := csvColumn(t, header, "Disk [sda] Throughput (IOPS)")
+	sdbIOPS := csvColumn(t, header, "Disk [sdb] Throughput (IOPS)")
+	veth0 := csvColumn(t, header, "Network [veth0] Throughput (Mbps)")
+	if records[1][sdaIOPS] != "2.00" || records[1][sdbIOPS] != "3.00" || records[1][veth0] != "1.00" {
+		t.Errorf("Unexpected row after roll: %v", records[1])
+	}
+	if records[2][sdaIOPS] != naString || records[2][sdbIOPS] != "4.00" || records[2][veth0] != naString {
+		t.Errorf("Vanished devices should be N/A: %v", records[2])
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, "dynamic_2.csv")); !os.IsNotExist(err) {
+		t.Error("Vanished devices must not trigger another roll")
+	}
+}
+
+func TestCSVExporter_DynamicSchemaRotationFailure(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "out")
+	if err := os.Mkdir(outputDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{OutputPath: filepath.Join(outputDir, "dynamic.csv"), Timezone: "UTC"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exp, err := NewCSVExporter(cfg, logger)
+	if err != nil {
+		t.Fatalf("NewCSVExporter() error = %v", err)
+	}
+	defer func() { _ = exp.Close() }()
+
+	if err := exp.Write(&metrics.Snapshot{Timestamp: time.Now(), Disks: map[string]metrics.DiskStats{"sda": {}}}); err != nil {
+		t.Fatal(err)
+	}
+	header := exp.layout.header()
+
+	// The rotated file cannot be created, so the new disk cannot get columns
+	if err := os.RemoveAll(outputDir); err != nil {
+		t.Fatal(err)
+	}
+	second := &metrics.Snapshot{Timestamp: time.Now(), Disks: map[string]metrics.DiskStats{"sda": {}, "sdb": {}}}
+	_ = exp.Write(second)
+
+	if !slices.Equal(exp.layout.header(), header) {
+		t.Errorf("Layout changed although rotation failed: %v", exp.layout.devices)
+	}
+	if got := len(exp.buildRow(second)); got != len(header) {
+		t.Errorf("Row has %d columns, want %d (header of the current file)", got, len(header))
+	}
+}
+
+func TestCSVExporter_CompressRotated(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "compress.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC", Compress: config.CompressGzip}
+
+	// A new disk forces a roll, closing the first segment
+	snapshots := []*metrics.Snapshot{
+		{Timestamp: time.Now(), Disks: map[string]metrics.DiskStats{"sda": {IOPS: 1}}},
+		{Timestamp: time.Now(), Disks: map[string]metrics.DiskStats{"sda": {IOPS: 2}, "sdb": {IOPS: 3}}},
+	}
+	if err := writeCSVRun(t, cfg, snapshots...); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Error("Rotated segment should be replaced by its compressed copy")
+	}
+
+	f, err := os.Open(outputPath + ".gz")
+	if err != nil {
+		t.Fatalf("Compressed segment missing: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	records, err := csv.NewReader(zr).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read compressed CSV: %v", err)
+	}
+	if len(records) != 2 || records[1][csvColumn(t, records[0], "Disk [sda] Throughput (IOPS)")] != "1.00" {
+		t.Errorf("Unexpected compressed content: %v", records)
+	}
+
+	// The active segment stays uncompressed
+	if got := len(readCSVRecords(t, filepath.Join(tempDir, "compress_1.csv"))); got != 2 {
+		t.Errorf("Active segment has %d records, want 2", got)
+	}
+}
+
+func TestCSVExporter_CompressRotatedMaxFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "compress.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC", Compress: config.CompressGzip, MaxFiles: 1}
+
+	snapshots := []*metrics.Snapshot{
+		{Timestamp: time.Now(), Disks: map[string]metrics.DiskStats{"sda": {}}},
+		{Timestamp: time.Now(), Disks: map[string]metrics.DiskStats{"sda": {}, "sdb": {}}},
+	}
+	if err := writeCSVRun(t, cfg, snapshots...); err != nil {
+		t.Fatal(err)
+	}
+
+	// The closed segment is pruned once compressed, not left behind as an untracked archive
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if !slices.Equal(names, []string{"compress_1.csv"}) {
+		t.Errorf("Files left = %v, want [compress_1.csv]", names)
+	}
+}
+
+func TestCSVExporter_RecoverTornLine(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "torn.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+	snapshot := &metrics.Snapshot{Timestamp: time.Now(), Disks: map[string]metrics.DiskStats{"sda": {}}}
+
+	if err := writeCSVRun(t, cfg, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a power loss in the middle of a row
+	f, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("2024-01-01 00:00:00,12.3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeCSVRun(t, cfg, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readCSVRecords(t, outputPath)
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows after recovery, got %d records", len(records))
+	}
+	for _, rec := range records {
+		if len(rec) != len(records[0]) {
+			t.Errorf("Torn row survived recovery: %v", rec)
+		}
+	}
+}
+
+func TestTruncateTornLine(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		content     string
+		want        string
+		wantRemoved int64
+	}{
+		{"", "", 0},
+		{"a,b\n1,2\n", "a,b\n1,2\n", 0},
+		{"a,b\n1,2\n3,", "a,b\n1,2\n", 2},
+		{"a,", "", 2},
+	}
+
+	for i, tt := range tests {
+		path := filepath.Join(tempDir, fmt.Sprintf("case%d.csv", i))
+		if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		removed, err := truncateTornLine(path)
+		if err != nil {
+			t.Fatalf("truncateTornLine(%q) error = %v", tt.content, err)
+		}
+		got, _ := os.ReadFile(path)
+		if string(got) != tt.want || removed != tt.wantRemoved {
+			t.Errorf("truncateTornLine(%q) = %q (removed %d), want %q (removed %d)",
+				tt.content, got, removed, tt.want, tt.wantRemoved)
+		}
+	}
+
+	if removed, err := truncateTornLine(filepath.Join(tempDir, "missing.csv")); err != nil || removed != 0 {
+		t.Errorf("Missing file: removed %d, error %v", removed, err)
+	}
+}
+
+func TestCSVExporter_FsyncEvery(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "fsync.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC", Fsync: config.FsyncEvery, FsyncEvery: 2}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	exp, err := NewCSVExporter(cfg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = exp.Close() }()
+
+	snapshot := &metrics.Snapshot{
+		Timestamp:    time.Now(),
+		TopProcesses: &metrics.TopProcesses{ByCPU: []metrics.TopProcess{{PID: 1, Name: "init", Value: 1}}},
+	}
+	for i := 0; i < 2; i++ {
+		if err := exp.Write(snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Every second record reaches the file without an explicit Flush, with its ranking
+	if got := len(readCSVRecords(t, outputPath)); got != 3 {
+		t.Errorf("Expected header and 2 rows on disk, got %d records", got)
+	}
+	sidecar, err := os.ReadFile(topSidecarPath(outputPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(sidecar), "\n"); got != 2 {
+		t.Errorf("Expected 2 rankings on disk, got %d", got)
+	}
+	if exp.unsynced != 0 {
+		t.Errorf("unsynced = %d after sync, want 0", exp.unsynced)
+	}
+
+	stat, err := os.Stat(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Size() != exp.currentSize {
+		t.Errorf("Tracked size = %d, file size = %d", exp.currentSize, stat.Size())
+	}
+}
+
+func TestCSVExporter_PerCoreColumns(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "cores.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+
+	snapshot := &metrics.Snapshot{
+		Timestamp: time.Now(),
+		CPU:       30.0,
+		CPUWait:   -1,
+		Memory:    40.0,
+		Cores: map[string]metrics.CoreStats{
+			"cpu10": {Utilization: 5.0, IOWait: -1},
+			"cpu2":  {Utilization: 95.0, IOWait: -1},
+		},
+	}
+	if err := writeCSVRun(t, cfg, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readCSVRecords(t, outputPath)
+	expectedColumns := []string{
+		"CPU Max Core Utilization (%)",
+		"CPU [cpu2] Utilization (%)", "CPU [cpu2] IO Wait (%)",
+		"CPU [cpu10] Utilization (%)", "CPU [cpu10] IO Wait (%)",
+		"Memory Utilization (%)",
+	}
+	first := csvColumn(t, records[0], "CPU Max Core Utilization (%)")
+	last := first + len(expectedColumns)
+	if !slices.Equal(records[0][first:last], expectedColumns) {
+		t.Errorf("Per-core columns = %v, want %v", records[0][first:last], expectedColumns)
+	}
+	expectedRow := []string{"95.00", "95.00", naString, "5.00", naString, "40.00"}
+	if !slices.Equal(records[1][first:last], expectedRow) {
+		t.Errorf("Per-core values = %v, want %v", records[1][first:last], expectedRow)
+	}
+}
+
+func TestCSVExporter_FilesystemColumns(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "filesystems.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+
+	first := &metrics.Snapshot{
+		Timestamp: time.Now(),
+		CPUWait:   -1,
+		Filesystems: map[string]metrics.FilesystemStats{
+			"/": {Used: 3 << 30, Free: 1 << 30, UsedPercent: 75, InodesUsed: 1200, InodesFree: 800, InodesUsedPercent: 60},
+		},
+	}
+	// /var/log is mounted mid-run; / disappears
+	second := &metrics.Snapshot{
+		Timestamp: first.Timestamp.Add(time.Second),
+		CPUWait:   -1,
+		Filesystems: map[string]metrics.FilesystemStats{
+			"/var/log": {Used: 512 << 20, Free: 512 << 20, UsedPercent: 50},
+		},
+	}
+	if err := writeCSVRun(t, cfg, first, second); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readCSVRecords(t, outputPath)
+	col := csvColumn(t, records[0], "Filesystem [/] Used (MB)")
+	expectedColumns := []string{
+		"Filesystem [/] Used (MB)", "Filesystem [/] Free (MB)", "Filesystem [/] Used (%)",
+		"Filesystem [/] Inodes Used", "Filesystem [/] Inodes Free", "Filesystem [/] Inodes Used (%)",
+	}
+	if !slices.Equal(records[0][col:col+filesystemColumns], expectedColumns) {
+		t.Errorf("Filesystem columns = %v, want %v", records[0][col:col+filesystemColumns], expectedColumns)
+	}
+	expectedRow := []string{"3072.00", "1024.00", "75.00", "1200", "800", "60.00"}
+	if !slices.Equal(records[1][col:col+filesystemColumns], expectedRow) {
+		t.Errorf("Filesystem values = %v, want %v", records[1][col:col+filesystemColumns], expectedRow)
+	}
+
+	// The new mountpoint rolls over to a file with both; the unmounted one is N/A
+	records = readCSVRecords(t, filepath.Join(filepath.Dir(outputPath), "filesystems_1.csv"))
+	col = csvColumn(t, records[0], "Filesystem [/] Used (MB)")
+	if got := records[1][col : col+filesystemColumns]; !slices.Equal(got, slices.Repeat([]string{naString}, filesystemColumns)) {
+		t.Errorf("Unmounted filesystem values = %v, want N/A", got)
+	}
+	if got := records[1][csvColumn(t, records[0], "Filesystem [/var/log] Used (%)")]; got != "50.00" {
+		t.Errorf("Filesystem [/var/log] Used (%%) = %q, want 50.00", got)
+	}
+}
+
+func TestCSVExporter_CgroupColumns(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "cgroups.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+
+	snapshot := &metrics.Snapshot{
+		Timestamp: time.Now(),
+		CPUWait:   -1,
+		Cgroups: map[string]metrics.CgroupStats{
+			"docker-0123456789ab": {
+				CPU: 150, CPUUser: 100, CPUSystem: 50, ThrottledPercent: 25, ThrottledTime: 12.5,
+				MemoryCurrent: 256 << 20, MemoryMax: 1 << 30, MemoryPercent: 25, PIDs: 12,
+				IO: map[string]metrics.CgroupIOStats{
+					"sda":   {ReadBytes: 2048, WriteBytes: 1024, ReadIOPS: 2, WriteIOPS: 1},
+					"259:0": {ReadBytes: 1024, WriteIOPS: 3},
+				},
+			},
+			"system.slice/app.service": {CPU: 5, MemoryCurrent: 64 << 20, MemoryPercent: -1, PIDs: 3},
+		},
+	}
+	if err := writeCSVRun(t, cfg, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readCSVRecords(t, outputPath)
+	col := csvColumn(t, records[0], "Cgroup [docker-0123456789ab] CPU (%)")
+	expectedRow := []string{"150.00", "100.00", "50.00", "25.00", "12.50", "256.00", "1024.00", "25.00", "12", "3.00", "1.00", "2.00", "4.00"}
+	if got := records[1][col : col+cgroupColumns]; !slices.Equal(got, expectedRow) {
+		t.Errorf("Cgroup values = %v, want %v", got, expectedRow)
+	}
+
+	// An unlimited cgroup has no memory limit or utilization
+	col = csvColumn(t, records[0], "Cgroup [system.slice/app.service] Memory Limit (MB)")
+	if got := records[1][col : col+2]; !slices.Equal(got, []string{naString, naString}) {
+		t.Errorf("Unlimited cgroup memory limit = %v, want N/A", got)
+	}
+}
+
+func TestCSVExporter_PressureColumns(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "pressure.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+
+	snapshot := &metrics.Snapshot{
+		Timestamp: time.Now(),
+		CPUWait:   -1,
+		Pressure: map[string]metrics.PressureStats{
+			"cpu":    {Some: 12.5, Full: -1, SomeAvg10: 10, SomeAvg60: 5, FullAvg10: -1, FullAvg60: -1},
+			"memory": {Some: 3, Full: 1.25, SomeAvg10: 2, SomeAvg60: 1, FullAvg10: 0.5, FullAvg60: 0.25},
+		},
+	}
+	if err := writeCSVRun(t, cfg, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readCSVRecords(t, outputPath)
+	for resource, want := range map[string][]string{
+		"cpu":    {"12.50", naString, "10.00", "5.00", naString, naString},
+		"memory": {"3.00", "1.25", "2.00", "1.00", "0.50", "0.25"},
+	} {
+		col := csvColumn(t, records[0], "Pressure ["+resource+"] Some (%)")
+		if got := records[1][col : col+pressureColumns]; !slices.Equal(got, want) {
+			t.Errorf("Pressure [%s] values = %v, want %v", resource, got, want)
+		}
+	}
+}
+
+func TestCSVExporter_SystemColumns(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "system.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+
+	first := &metrics.Snapshot{Timestamp: time.Now(), CPUWait: -1}
+	second := &metrics.Snapshot{
+		Timestamp: first.Timestamp.Add(time.Second),
+		CPUWait:   -1,
+		System: &metrics.SystemStats{
+			Load1: 1.5, Load5: 1.25, Load15: 0.75, ContextSwitches: 12000, Interrupts: 8000.5, Forks: 3,
+			ProcsRunning: 5, ProcsBlocked: 1,
+		},
+	}
+	third := &metrics.Snapshot{Timestamp: second.Timestamp.Add(time.Second), CPUWait: -1}
+	if err := writeCSVRun(t, cfg, first, second, third); err != nil {
+		t.Fatal(err)
+	}
+
+	if records := readCSVRecords(t, outputPath); slices.Contains(records[0], "System Load 1m") {
+		t.Errorf("System columns written before the group was collected: %v", records[0])
+	}
+
+	// The group appears mid-run: roll over to a file with its columns
+	records := readCSVRecords(t, filepath.Join(filepath.Dir(outputPath), "system_1.csv"))
+	col := csvColumn(t, records[0], "System Load 1m")
+	expectedRow := []string{"1.50", "1.25", "0.75", "12000.00", "8000.50", "3.00", "5", "1"}
+	if got := records[1][col : col+systemColumns]; !slices.Equal(got, expectedRow) {
+		t.Errorf("System values = %v, want %v", got, expectedRow)
+	}
+	if got := records[2][col : col+systemColumns]; !slices.Equal(got, slices.Repeat([]string{naString}, systemColumns)) {
+		t.Errorf("Missing system values = %v, want N/A", got)
+	}
+}
+
+func TestCSVExporter_NetstatColumns(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "netstat.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+
+	states := make(map[string]uint64, len(metrics.TCPStates))
+	for _, state := range metrics.TCPStates {
+		states[state] = 0
+	}
+	states["ESTABLISHED"], states["TIME_WAIT"] = 42, 7
+	snapshot := &metrics.Snapshot{
+		Timestamp: time.Now(),
+		CPUWait:   -1,
+		Netstat: &metrics.NetstatStats{
+			TCPStates: states, ActiveOpens: 1.5, PassiveOpens: 20, RetransSegs: 0.25, ListenOverflows: 3,
+			ListenDrops: 4, UDPInErrors: 0.5, UDPRcvbufErrors: 0.1,
+		},
+	}
+	if err := writeCSVRun(t, cfg, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readCSVRecords(t, outputPath)
+	col := csvColumn(t, records[0], "TCP [ESTABLISHED] Connections")
+	if got := records[1][col]; got != "42" {
+		t.Errorf("TCP [ESTABLISHED] Connections = %q, want 42", got)
+	}
+	if got := records[1][csvColumn(t, records[0], "TCP [TIME_WAIT] Connections")]; got != "7" {
+		t.Errorf("TCP [TIME_WAIT] Connections = %q, want 7", got)
+	}
+	col = csvColumn(t, records[0], "TCP Active Opens (/s)")
+	expectedRow := []string{"1.50", "20.00", "0.25", "3.00", "4.00", "0.50", "0.10"}
+	if got := records[1][col : col+netstatRateColumns]; !slices.Equal(got, expectedRow) {
+		t.Errorf("Netstat rates = %v, want %v", got, expectedRow)
+	}
+}
+
+func TestCSVExporter_TopSidecar(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "top.csv")
+	cfg := &config.Config{OutputPath: outputPath, Timezone: "UTC"}
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	top := &metrics.TopProcesses{
+		ByCPU: []metrics.TopProcess{{PID: 42, Name: "java", Value: 180.5}},
+		ByRSS: []metrics.TopProcess{{PID: 42, Name: "java", Value: 2 << 30}},
+	}
+	snapshots := []*metrics.Snapshot{
+		{Timestamp: start, CPUWait: -1, TopProcesses: top},
+		{Timestamp: start.Add(time.Second), CPUWait: -1}, // Top collector skipped this interval
+		// A new disk rolls the CSV; the ranking follows it to the new file's sidecar
+		{Timestamp: start.Add(2 * time.Second), CPUWait: -1, Disks: map[string]metrics.DiskStats{"sdb": {}}, TopProcesses: top},
+	}
+	if err := writeCSVRun(t, cfg, snapshots...); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "top.top.jsonl"))
+	if err != nil {
+		t.Fatalf("sidecar missing: %v", err)
+	}
+	want := `{"timestamp":"2024-01-02T03:04:05Z","by_cpu":[{"pid":42,"name":"java","value":180.5}],` +
+		`"by_rss":[{"pid":42,"name":"java","value":2147483648}]}` + "\n"
+	if string(data) != want {
+		t.Errorf("sidecar =\n%s\nwant\n%s", data, want)
+	}
+
+	data, err = os.ReadFile(filepath.Join(tempDir, "top_1.top.jsonl"))
+	if err != nil {
+		t.Fatalf("sidecar of the rotated file missing: %v", err)
+	}
+	if !strings.HasPrefix(string(data), `{"timestamp":"2024-01-02T03:04:07Z"`) || strings.Count(string(data), "\n") != 1 {
+		t.Errorf("rotated sidecar = %s", data)
+	}
+}
+
+func TestTopSidecarCompanions(t *testing.T) {
+	want := []string{"/out/host_3.top.jsonl", "/out/host_3.top.jsonl.gz"}
+	for _, csvPath := range []string{"/out/host_3.csv", "/out/host_3.csv.gz"} {
+		if got := topSidecarCompanions(csvPath); !slices.Equal(got, want) {
+			t.Errorf("topSidecarCompanions(%q) = %v, want %v", csvPath, got, want)
+		}
+	}
+}
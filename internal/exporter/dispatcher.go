@@ -0,0 +1,251 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+// Dispatcher fans out snapshots from a single metrics channel to multiple sinks.
+//
+// Every sink gets its own queue and goroutine, so a slow sink only drops its own
+// snapshots (once its queue is full) and never stalls the others.
+type Dispatcher struct {
+	config      *config.Config
+	metricsChan <-chan *metrics.Snapshot
+	sinks       []*sink
+	logger      *slog.Logger
+}
+
+// sink is the per-exporter state owned by the Dispatcher.
+type sink struct {
+	exporter    Exporter
+	queue       chan *metrics.Snapshot
+	recordCount int // Records written since the last flush
+	dropped     int // Snapshots dropped because the queue was full
+	closed      bool
+}
+
+// NewDispatcher creates a dispatcher that feeds the given exporters.
+func NewDispatcher(cfg *config.Config, metricsChan <-chan *metrics.Snapshot, exporters []Exporter, logger *slog.Logger) *Dispatcher {
+	queueSize := cfg.SinkQueueSize
+	if queueSize < 1 {
+		queueSize = config.DefaultSinkQueueSize
+	}
+
+	sinks := make([]*sink, 0, len(exporters))
+	for _, exp := range exporters {
+		sinks = append(sinks, &sink{
+			exporter: exp,
+			queue:    make(chan *metrics.Snapshot, queueSize),
+		})
+	}
+
+	return &Dispatcher{
+		config:      cfg,
+		metricsChan: metricsChan,
+		sinks:       sinks,
+		logger:      logger,
+	}
+}
+
+// Start starts every sink and dispatches snapshots until the metrics channel is closed
+// or the context is cancelled. It returns once every sink has drained and flushed its queue.
+// A sink failing with ErrFatal stops the dispatcher and its error is returned.
+// If a sink fails to start, the sinks already started are closed in reverse order.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for i, s := range d.sinks {
+		if err := s.exporter.Start(ctx); err != nil {
+			for _, started := range slices.Backward(d.sinks[:i]) {
+				if err := started.close(); err != nil {
+					d.logger.Error("Failed to close sink", "sink", started.exporter.Name(), "error", err)
+				}
+			}
+			return fmt.Errorf("failed to start %s sink: %w", s.exporter.Name(), err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(d.sinks))
+	for i, s := range d.sinks {
+		wg.Add(1)
+		go func(i int, s *sink) {
+			defer wg.Done()
+			errs[i] = d.runSink(s)
+			if errs[i] != nil && errors.Is(errs[i], ErrFatal) {
+				cancel()
+			}
+		}(i, s)
+	}
+
+	d.dispatch(ctx)
+
+	// Closing the queues lets every sink drain and exit
+	for _, s := range d.sinks {
+		close(s.queue)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// dispatch copies every snapshot from the metrics channel to all sink queues.
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Dispatcher stopping...")
+			// Hand over snapshots that are already waiting in the channel
+			for {
+				select {
+				case snapshot, ok := <-d.metricsChan:
+					if !ok {
+						return
+					}
+					d.enqueue(snapshot)
+				default:
+					return
+				}
+			}
+
+		case snapshot, ok := <-d.metricsChan:
+			if !ok {
+				d.logger.Info("Metrics channel closed, flushing remaining data...")
+				return
+			}
+			d.enqueue(snapshot)
+		}
+	}
+}
+
+// enqueue offers a snapshot to every sink without blocking.
+func (d *Dispatcher) enqueue(snapshot *metrics.Snapshot) {
+	for _, s := range d.sinks {
+		select {
+		case s.queue <- snapshot:
+		default:
+			s.dropped++
+			d.logger.Warn("Sink queue full, dropping snapshot",
+				"sink", s.exporter.Name(),
+				"dropped_total", s.dropped,
+			)
+		}
+	}
+}
+
+// runSink writes queued snapshots to a single exporter, flushing when the buffer
+// size is reached or the flush interval elapses.
+func (d *Dispatcher) runSink(s *sink) error {
+	flushTicker := time.NewTicker(d.config.FlushInterval)
+	defer flushTicker.Stop()
+
+	name := s.exporter.Name()
+
+	for {
+		select {
+		case snapshot, ok := <-s.queue:
+			if !ok {
+				// Queue closed, flush and exit
+				return d.flushSink(s)
+			}
+
+			if err := s.exporter.Write(snapshot); err != nil {
+				if errors.Is(err, ErrFatal) {
+					d.logger.Error("Sink failed, stopping", "sink", name, "error", err)
+					d.discard(s)
+					return fmt.Errorf("%s sink: %w", name, err)
+				}
+				d.logger.Error("Failed to write snapshot", "sink", name, "error", err)
+			}
+
+			s.recordCount++
+
+			// Flush if buffer size reached
+			if s.recordCount >= d.config.BufferSize {
+				if err := d.flushSink(s); err != nil {
+					d.logger.Error("Failed to flush", "sink", name, "error", err)
+				}
+			}
+
+		case <-flushTicker.C:
+			// Time-based flush
+			if s.recordCount > 0 {
+				if err := d.flushSink(s); err != nil {
+					d.logger.Error("Failed to flush", "sink", name, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// discard drains a failed sink's queue so the dispatcher never blocks on it.
+func (d *Dispatcher) discard(s *sink) {
+	go func() {
+		for range s.queue {
+			continue
+		}
+	}()
+}
+
+// flushSink flushes a sink and resets its record counter.
+func (d *Dispatcher) flushSink(s *sink) error {
+	s.recordCount = 0
+	if err := s.exporter.Flush(); err != nil {
+		return fmt.Errorf("%s sink: %w", s.exporter.Name(), err)
+	}
+	return nil
+}
+
+// Close closes every sink that is not closed yet.
+func (d *Dispatcher) Close() error {
+	var errs []error
+	for _, s := range d.sinks {
+		if err := s.close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s sink: %w", s.exporter.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// close closes the exporter once.
+func (s *sink) close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.exporter.Close()
+}
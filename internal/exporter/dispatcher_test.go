@@ -0,0 +1,259 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+// memoryExporter records written snapshots in memory.
+type memoryExporter struct {
+	name     string
+	delay    time.Duration // Artificial per-write latency
+	err      error         // Error returned by Write
+	startErr error         // Error returned by Start
+	mu       sync.Mutex
+	written  []*metrics.Snapshot
+	started  bool
+	flushes  int
+	closes   int
+}
+
+func (m *memoryExporter) Name() string { return m.name }
+
+func (m *memoryExporter) Start(_ context.Context) error {
+	if m.startErr != nil {
+		return m.startErr
+	}
+	m.started = true
+	return nil
+}
+
+func (m *memoryExporter) Write(snapshot *metrics.Snapshot) error {
+	time.Sleep(m.delay)
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.written = append(m.written, snapshot)
+	return nil
+}
+
+func (m *memoryExporter) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flushes++
+	return nil
+}
+
+func (m *memoryExporter) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closes++
+	return nil
+}
+
+func (m *memoryExporter) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.written)
+}
+
+func TestDispatcher_FanOut(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		FlushInterval: 50 * time.Millisecond,
+		BufferSize:    2,
+	}
+	metricsChan := make(chan *metrics.Snapshot, 10)
+	a := &memoryExporter{name: "a"}
+	b := &memoryExporter{name: "b"}
+
+	d := NewDispatcher(cfg, metricsChan, []Exporter{a, b}, logger)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Start(context.Background())
+	}()
+
+	for i := 0; i < 5; i++ {
+		metricsChan <- &metrics.Snapshot{Timestamp: time.Now(), CPU: float64(i)}
+	}
+	close(metricsChan)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	for _, exp := range []*memoryExporter{a, b} {
+		if exp.count() != 5 {
+			t.Errorf("sink %s received %d snapshots, want 5", exp.name, exp.count())
+		}
+		if exp.flushes < 3 { // 2 buffer-size flushes + final flush
+			t.Errorf("sink %s flushed %d times, want >= 3", exp.name, exp.flushes)
+		}
+		if exp.closes == 0 {
+			t.Errorf("sink %s was not closed", exp.name)
+		}
+	}
+}
+
+func TestDispatcher_SlowSinkDoesNotStallOthers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		FlushInterval: time.Second,
+		BufferSize:    100,
+		SinkQueueSize: 1,
+	}
+	metricsChan := make(chan *metrics.Snapshot, 100)
+	fast := &memoryExporter{name: "fast"}
+	slow := &memoryExporter{name: "slow", delay: 200 * time.Millisecond}
+
+	d := NewDispatcher(cfg, metricsChan, []Exporter{fast, slow}, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Start(ctx)
+	}()
+
+	for i := 0; i < 20; i++ {
+		metricsChan <- &metrics.Snapshot{Timestamp: time.Now()}
+		time.Sleep(time.Millisecond)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for fast.count() < 20 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if fast.count() != 20 {
+		t.Errorf("fast sink received %d snapshots, want 20", fast.count())
+	}
+
+	close(metricsChan)
+	if err := <-done; err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if slow.count() >= 20 {
+		t.Errorf("slow sink received %d snapshots, expected drops with queue size 1", slow.count())
+	}
+}
+
+func TestDispatcher_FatalSinkStopsPipeline(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{FlushInterval: time.Second, BufferSize: 10}
+	metricsChan := make(chan *metrics.Snapshot, 10)
+	ok := &memoryExporter{name: "ok"}
+	broken := &memoryExporter{name: "broken", err: fmt.Errorf("%w: disk gone", ErrFatal)}
+
+	d := NewDispatcher(cfg, metricsChan, []Exporter{ok, broken}, logger)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Start(context.Background())
+	}()
+
+	metricsChan <- &metrics.Snapshot{Timestamp: time.Now()}
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrFatal) {
+			t.Errorf("Start() error = %v, want ErrFatal", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Dispatcher did not stop after a fatal sink error")
+	}
+}
+
+func TestDispatcher_StartFailureClosesStartedSinks(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{FlushInterval: time.Second, BufferSize: 10}
+	metricsChan := make(chan *metrics.Snapshot, 10)
+	first := &memoryExporter{name: "first"}
+	second := &memoryExporter{name: "second", startErr: errors.New("address already in use")}
+	third := &memoryExporter{name: "third"}
+
+	d := NewDispatcher(cfg, metricsChan, []Exporter{first, second, third}, logger)
+	if err := d.Start(context.Background()); err == nil {
+		t.Fatal("Start() error = nil, want the start failure of the second sink")
+	}
+
+	if first.closes == 0 {
+		t.Error("first sink was started but not closed")
+	}
+	if third.started || third.closes > 0 {
+		t.Error("third sink must be neither started nor closed by Start")
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for _, exp := range []*memoryExporter{first, second, third} {
+		if exp.closes != 1 {
+			t.Errorf("sink %s closed %d times, want 1", exp.name, exp.closes)
+		}
+	}
+}
+
+func TestBuild_Sinks(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		OutputPath: tempDir + "/build.csv",
+		Timezone:   "UTC",
+	}
+
+	exporters, err := Build(cfg, logger)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(exporters) != 1 || exporters[0].Name() != DefaultSink {
+		t.Fatalf("Build() with no sinks should create the %s sink", DefaultSink)
+	}
+	closeAll(exporters, logger)
+
+	cfg.Sinks = []string{"nope"}
+	if _, err := Build(cfg, logger); err == nil {
+		t.Error("Build() expected error for unknown sink")
+	}
+	if err := ValidateNames([]string{"CSV"}); err != nil {
+		t.Errorf("ValidateNames() unexpected error = %v", err)
+	}
+}
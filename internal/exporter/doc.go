@@ -24,6 +24,8 @@
 
 // Package exporter implements data export pipelines.
 //
-// It primarily contains the CSVExporter which receives metric Snapshots
-// and writes them to disk in a structured CSV format, handling buffering and flushing.
+// Sinks implement the Exporter interface and are selected by name from a registry.
+// The Dispatcher fans metric Snapshots out to every configured sink, handling
+// per-sink buffering and flushing. The CSVExporter writes them to disk in a
+// structured CSV format.
 package exporter
@@ -0,0 +1,163 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+// DefaultSink is the sink used when no sink is configured.
+const DefaultSink = "csv"
+
+var (
+	// ErrFatal marks write errors after which a sink cannot continue.
+	// The Dispatcher stops the whole pipeline when a sink returns such an error.
+	ErrFatal = errors.New("fatal sink error")
+
+	// ErrSchemaMismatch reports that an existing output file is incompatible with the current data.
+	ErrSchemaMismatch = errors.New("schema mismatch")
+)
+
+// Exporter is a destination (sink) for metric snapshots.
+//
+// Start prepares the sink before the first write. Write may buffer data;
+// Flush pushes buffered data to the underlying storage. Close releases all
+// resources after a final flush. A single exporter is only ever driven by
+// one goroutine, so implementations do not need internal locking.
+type Exporter interface {
+	Name() string
+	Start(ctx context.Context) error
+	Write(snapshot *metrics.Snapshot) error
+	Flush() error
+	Close() error
+}
+
+// Factory builds an exporter from the application configuration.
+type Factory func(cfg *config.Config, logger *slog.Logger) (Exporter, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register adds an exporter factory to the registry under the given sink name.
+// Names are case-insensitive. Registering a name twice panics, as it indicates a programming error.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	key := strings.ToLower(name)
+	if _, exists := registry[key]; exists {
+		panic(fmt.Sprintf("exporter %q already registered", name))
+	}
+	registry[key] = factory
+}
+
+// Registered returns the names of all registered sinks, sorted alphabetically.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateNames checks that every name refers to a registered sink.
+func ValidateNames(names []string) error {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, name := range names {
+		if _, ok := registry[strings.ToLower(name)]; !ok {
+			available := make([]string, 0, len(registry))
+			for n := range registry {
+				available = append(available, n)
+			}
+			sort.Strings(available)
+			return fmt.Errorf("unknown sink: %s (available: %s)", name, strings.Join(available, ", "))
+		}
+	}
+	return nil
+}
+
+// Build instantiates the sinks selected by the configuration.
+// An empty Sinks list selects DefaultSink. Duplicate names are created once.
+// If any sink fails to build, the already created sinks are closed.
+func Build(cfg *config.Config, logger *slog.Logger) ([]Exporter, error) {
+	names := cfg.Sinks
+	if len(names) == 0 {
+		names = []string{DefaultSink}
+	}
+
+	exporters := make([]Exporter, 0, len(names))
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		registryMu.RLock()
+		factory, ok := registry[key]
+		registryMu.RUnlock()
+		if !ok {
+			closeAll(exporters, logger)
+			return nil, fmt.Errorf("unknown sink: %s", name)
+		}
+
+		exp, err := factory(cfg, logger)
+		if err != nil {
+			closeAll(exporters, logger)
+			return nil, fmt.Errorf("failed to create %s sink: %w", key, err)
+		}
+		exporters = append(exporters, exp)
+	}
+
+	return exporters, nil
+}
+
+// closeAll closes every exporter, logging failures.
+func closeAll(exporters []Exporter, logger *slog.Logger) {
+	for _, exp := range exporters {
+		if err := exp.Close(); err != nil {
+			logger.Error("Failed to close exporter", "sink", exp.Name(), "error", err)
+		}
+	}
+}
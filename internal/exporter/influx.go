@@ -0,0 +1,586 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func init() {
+	Register("influx", func(cfg *config.Config, logger *slog.Logger) (Exporter, error) {
+		return NewInfluxExporter(cfg, logger)
+	})
+}
+
+// Retry policy for HTTP writes. Variables so tests can shorten them.
+var (
+	influxMaxAttempts    = 3
+	influxInitialBackoff = 500 * time.Millisecond
+	influxRequestTimeout = 10 * time.Second
+)
+
+// influxSpoolChunkSize bounds the size of a single request when replaying the spool.
+const influxSpoolChunkSize = 1024 * 1024 // 1MB
+
+// InfluxExporter serializes snapshots to InfluxDB line protocol.
+//
+// Without an endpoint URL it appends lines to a local `.lp` file. With an endpoint
+// it batches lines between flushes and POSTs them to an `/api/v2/write`-compatible
+// endpoint, retrying transport errors, 429 and 5xx responses with exponential backoff.
+// Batches that cannot be delivered are appended to an on-disk spool and replayed once
+// the endpoint is reachable again. Batches the endpoint rejects with another 4xx are
+// logged and dropped, since retrying them can never succeed.
+type InfluxExporter struct {
+	config    *config.Config
+	logger    *slog.Logger
+	hostname  string
+	client    *http.Client
+	writeURL  string          // Empty in file mode
+	pending   bytes.Buffer    // Lines waiting for the next flush (HTTP mode)
+	file      *os.File        // Output file (file mode)
+	bufWriter *bufio.Writer   // Buffered writer on file (file mode)
+	spoolPath string          // Spool file for undelivered batches (HTTP mode)
+	stop      <-chan struct{} // Closed on shutdown to cut retry backoffs short
+}
+
+// influxStatusError reports a non-2xx response from the write endpoint.
+type influxStatusError struct {
+	status int
+	body   string
+}
+
+func (e *influxStatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.status, e.body)
+}
+
+// influxRetryable reports whether a failed write may succeed later: transport errors,
+// 429 Too Many Requests and 5xx responses. Other statuses reject the batch itself.
+func influxRetryable(err error) bool {
+	var statusErr *influxStatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	return statusErr.status == http.StatusTooManyRequests || statusErr.status >= 500
+}
+
+// influxOutputPath derives the line protocol output path from the configured CSV output path.
+func influxOutputPath(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".lp"
+}
+
+// NewInfluxExporter creates a new InfluxDB line protocol exporter instance.
+func NewInfluxExporter(cfg *config.Config, logger *slog.Logger) (*InfluxExporter, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	e := &InfluxExporter{
+		config:   cfg,
+		logger:   logger,
+		hostname: hostname,
+	}
+
+	if cfg.InfluxURL == "" {
+		path := influxOutputPath(cfg.OutputPath)
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open output file: %w", err)
+		}
+		e.file = file
+		e.bufWriter = bufio.NewWriterSize(file, 8192) // 8KB buffer
+		return e, nil
+	}
+
+	writeURL, err := buildInfluxWriteURL(cfg)
+	if err != nil {
+		return nil, err
+	}
+	e.writeURL = writeURL
+	e.client = &http.Client{Timeout: influxRequestTimeout}
+	e.spoolPath = cfg.InfluxSpoolPath
+	if e.spoolPath == "" {
+		e.spoolPath = strings.TrimSuffix(cfg.OutputPath, filepath.Ext(cfg.OutputPath)) + ".spool.lp"
+	}
+
+	return e, nil
+}
+
+// buildInfluxWriteURL builds the write endpoint URL with org, bucket and precision parameters.
+// The `/api/v2/write` path is appended unless the configured URL already ends with it.
+func buildInfluxWriteURL(cfg *config.Config) (string, error) {
+	u, err := url.Parse(cfg.InfluxURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid InfluxDB URL: %s", cfg.InfluxURL)
+	}
+
+	if !strings.HasSuffix(u.Path, "/api/v2/write") {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/api/v2/write"
+	}
+
+	q := u.Query()
+	if cfg.InfluxOrg != "" {
+		q.Set("org", cfg.InfluxOrg)
+	}
+	if cfg.InfluxBucket != "" {
+		q.Set("bucket", cfg.InfluxBucket)
+	}
+	q.Set("precision", "ns")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Name returns the sink name.
+func (e *InfluxExporter) Name() string {
+	return "influx"
+}
+
+// Start logs the exporter settings. Cancelling ctx stops waiting between retries.
+func (e *InfluxExporter) Start(ctx context.Context) error {
+	e.stop = ctx.Done()
+	if e.file != nil {
+		e.logger.Info("Starting InfluxDB exporter", "output", e.file.Name())
+	} else {
+		e.logger.Info("Starting InfluxDB exporter", "endpoint", redactURL(e.writeURL), "spool", e.spoolPath)
+	}
+	return nil
+}
+
+// Write serializes a snapshot to line protocol and buffers it until the next flush.
+func (e *InfluxExporter) Write(snapshot *metrics.Snapshot) error {
+	lines := formatLineProtocol(snapshot, e.hostname)
+
+	if e.file != nil {
+		if _, err := e.bufWriter.WriteString(lines); err != nil {
+			return fmt.Errorf("failed to write lines: %w", err)
+		}
+		return nil
+	}
+
+	e.pending.WriteString(lines)
+	return nil
+}
+
+// Flush writes buffered lines to the file, or sends the pending batch to the endpoint.
+func (e *InfluxExporter) Flush() error {
+	if e.file != nil {
+		if err := e.bufWriter.Flush(); err != nil {
+			return fmt.Errorf("buffer writer error: %w", err)
+		}
+		return nil
+	}
+
+	if e.pending.Len() == 0 {
+		return nil
+	}
+
+	batch := bytes.Clone(e.pending.Bytes())
+	e.pending.Reset()
+
+	// Older batches go first to keep points roughly in order
+	if err := e.replaySpool(); err != nil {
+		return e.spool(batch, err)
+	}
+
+	if err := e.send(batch); err != nil {
+		if !influxRetryable(err) {
+			return fmt.Errorf("endpoint rejected batch, dropped %d bytes: %w", len(batch), err)
+		}
+		return e.spool(batch, err)
+	}
+
+	return nil
+}
+
+// send POSTs a batch to the endpoint, retrying with exponential backoff while the
+// error is retryable and the exporter is not shutting down.
+func (e *InfluxExporter) send(batch []byte) error {
+	backoff := influxInitialBackoff
+	var lastErr error
+
+	for attempt := 1; attempt <= influxMaxAttempts; attempt++ {
+		lastErr = e.post(batch)
+		if lastErr == nil || !influxRetryable(lastErr) {
+			return lastErr
+		}
+
+		if attempt < influxMaxAttempts {
+			e.logger.Debug("InfluxDB write failed, retrying", "attempt", attempt, "backoff", backoff, "error", lastErr)
+			select {
+			case <-e.stop:
+				return lastErr
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+
+	return lastErr
+}
+
+// post performs a single write request.
+func (e *InfluxExporter) post(batch []byte) error {
+	req, err := http.NewRequest(http.MethodPost, e.writeURL, bytes.NewReader(batch))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if e.config.InfluxToken != "" {
+		req.Header.Set("Authorization", "Token "+e.config.InfluxToken)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &influxStatusError{status: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+
+	return nil
+}
+
+// spool appends an undelivered batch to the spool file.
+// The spool is capped at config.DefaultMaxOutputFileSize; batches beyond that are dropped.
+func (e *InfluxExporter) spool(batch []byte, cause error) error {
+	if stat, err := os.Stat(e.spoolPath); err == nil && stat.Size()+int64(len(batch)) > config.DefaultMaxOutputFileSize {
+		return fmt.Errorf("endpoint unreachable and spool full, dropped %d bytes: %w", len(batch), cause)
+	}
+
+	f, err := os.OpenFile(e.spoolPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open spool file: %w (write error: %w)", err, cause)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(batch); err != nil {
+		return fmt.Errorf("failed to write spool file: %w (write error: %w)", err, cause)
+	}
+
+	return fmt.Errorf("endpoint unreachable, spooled %d bytes: %w", len(batch), cause)
+}
+
+// replaySpool sends spooled batches in chunks. Delivered and rejected chunks are removed
+// from the spool; on a retryable failure the remainder is kept and the error is returned.
+func (e *InfluxExporter) replaySpool() error {
+	data, err := os.ReadFile(e.spoolPath)
+	if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read spool file: %w", err)
+	}
+
+	e.logger.Info("Replaying spooled InfluxDB lines", "bytes", len(data))
+
+	for len(data) > 0 {
+		chunk := nextSpoolChunk(data)
+		if err := e.send(chunk); err != nil && !influxRetryable(err) {
+			e.logger.Error("Endpoint rejected spooled InfluxDB lines, dropping them", "bytes", len(chunk), "error", err)
+		} else if err != nil {
+			if werr := os.WriteFile(e.spoolPath, data, 0o644); werr != nil {
+				e.logger.Error("Failed to rewrite spool file", "error", werr)
+			}
+			return err
+		}
+		data = data[len(chunk):]
+	}
+
+	if err := os.Remove(e.spoolPath); err != nil {
+		return fmt.Errorf("failed to remove spool file: %w", err)
+	}
+	return nil
+}
+
+// nextSpoolChunk returns a prefix of data of at most influxSpoolChunkSize bytes,
+// ending at a line boundary when possible.
+func nextSpoolChunk(data []byte) []byte {
+	if len(data) <= influxSpoolChunkSize {
+		return data
+	}
+	if idx := bytes.LastIndexByte(data[:influxSpoolChunkSize], '\n'); idx >= 0 {
+		return data[:idx+1]
+	}
+	return data[:influxSpoolChunkSize]
+}
+
+// Close flushes remaining data and closes the output file.
+func (e *InfluxExporter) Close() error {
+	e.logger.Info("Closing InfluxDB exporter")
+
+	if err := e.Flush(); err != nil {
+		e.logger.Error("Final flush failed", "error", err)
+	}
+
+	if e.file != nil {
+		if err := e.file.Close(); err != nil {
+			return fmt.Errorf("failed to close file: %w", err)
+		}
+	}
+
+	e.logger.Info("InfluxDB exporter closed")
+	return nil
+}
+
+// formatLineProtocol serializes a snapshot to InfluxDB line protocol with nanosecond timestamps.
+func formatLineProtocol(snapshot *metrics.Snapshot, hostname string) string {
+	var sb strings.Builder
+	ts := strconv.FormatInt(snapshot.Timestamp.UnixNano(), 10)
+	hostTag := "host=" + escapeInfluxTag(hostname)
+
+	writeLine := func(measurement, tags string, fields []string) {
+		sb.WriteString(measurement)
+		sb.WriteByte(',')
+		sb.WriteString(tags)
+		sb.WriteByte(' ')
+		sb.WriteString(strings.Join(fields, ","))
+		sb.WriteByte(' ')
+		sb.WriteString(ts)
+		sb.WriteByte('\n')
+	}
+
+	cpuFields := []string{influxField("utilization", snapshot.CPU)}
+	if snapshot.CPUWait >= 0 {
+		cpuFields = append(cpuFields, influxField("iowait", snapshot.CPUWait))
+	}
+	for _, mode := range cpuModes(snapshot.CPUTimes) {
+		cpuFields = append(cpuFields, influxField(mode.name, mode.value))
+	}
+	if _, maxUtil, ok := snapshot.MaxCore(); ok {
+		cpuFields = append(cpuFields, influxField("max_core_utilization", maxUtil))
+	}
+	writeLine("unostat_cpu", hostTag, cpuFields)
+
+	for _, core := range sortedCoreNames(snapshot.Cores) {
+		stats := snapshot.Cores[core]
+		coreFields := []string{influxField("utilization", stats.Utilization)}
+		if stats.IOWait >= 0 {
+			coreFields = append(coreFields, influxField("iowait", stats.IOWait))
+		}
+		writeLine("unostat_cpu_core", hostTag+",core="+escapeInfluxTag(core), coreFields)
+	}
+	mem := snapshot.MemDetail
+	writeLine("unostat_memory", hostTag, []string{
+		influxField("utilization", snapshot.Memory),
+		influxField("available_bytes", float64(mem.Available)),
+		influxField("cached_bytes", float64(mem.Cached)),
+		influxField("buffers_bytes", float64(mem.Buffers)),
+		influxField("dirty_bytes", float64(mem.Dirty)),
+		influxField("writeback_bytes", float64(mem.WriteBack)),
+		influxField("slab_bytes", float64(mem.Slab)),
+		influxField("swap_used_bytes", float64(mem.SwapUsed)),
+		influxField("swap_total_bytes", float64(mem.SwapTotal)),
+		influxField("swap_in_pages_per_sec", mem.SwapIn),
+		influxField("swap_out_pages_per_sec", mem.SwapOut),
+	})
+
+	for _, device := range sortedKeys(snapshot.Disks) {
+		stats := snapshot.Disks[device]
+		writeLine("unostat_disk", hostTag+",device="+escapeInfluxTag(device), []string{
+			influxField("utilization", stats.Utilization),
+			influxField("await_ms", stats.Await),
+			influxField("iops", stats.IOPS),
+			influxField("read_iops", stats.ReadIOPS),
+			influxField("write_iops", stats.WriteIOPS),
+			influxField("read_bytes_per_sec", stats.ReadThroughput),
+			influxField("write_bytes_per_sec", stats.WriteThroughput),
+			influxField("read_await_ms", stats.ReadAwait),
+			influxField("write_await_ms", stats.WriteAwait),
+			influxField("avg_request_bytes", stats.AvgRequestSize),
+			influxField("avg_queue_size", stats.QueueDepth),
+		})
+	}
+
+	for _, iface := range sortedKeys(snapshot.Networks) {
+		stats := snapshot.Networks[iface]
+		writeLine("unostat_network", hostTag+",interface="+escapeInfluxTag(iface), []string{
+			influxField("bandwidth_bps", stats.Bandwidth),
+			influxField("rx_bps", stats.RxBandwidth),
+			influxField("tx_bps", stats.TxBandwidth),
+			influxField("rx_packets_per_sec", stats.RxPackets),
+			influxField("tx_packets_per_sec", stats.TxPackets),
+			influxField("errin_per_sec", stats.ErrIn),
+			influxField("errout_per_sec", stats.ErrOut),
+			influxField("dropin_per_sec", stats.DropIn),
+			influxField("dropout_per_sec", stats.DropOut),
+		})
+	}
+
+	for _, mount := range sortedKeys(snapshot.Filesystems) {
+		stats := snapshot.Filesystems[mount]
+		tags := hostTag + ",mountpoint=" + escapeInfluxTag(mount) + ",fstype=" + escapeInfluxTag(stats.Fstype)
+		writeLine("unostat_filesystem", tags, []string{
+			influxField("total_bytes", float64(stats.Total)),
+			influxField("used_bytes", float64(stats.Used)),
+			influxField("free_bytes", float64(stats.Free)),
+			influxField("used_percent", stats.UsedPercent),
+			influxField("inodes_total", float64(stats.InodesTotal)),
+			influxField("inodes_used", float64(stats.InodesUsed)),
+			influxField("inodes_free", float64(stats.InodesFree)),
+			influxField("inodes_used_percent", stats.InodesUsedPercent),
+		})
+	}
+
+	for _, proc := range sortedKeys(snapshot.Processes) {
+		stats := snapshot.Processes[proc]
+		writeLine("unostat_process", hostTag+",process="+escapeInfluxTag(proc), []string{
+			influxField("count", float64(stats.Count)),
+			influxField("cpu", stats.CPU),
+			influxField("rss_bytes", float64(stats.RSS)),
+			influxField("threads", float64(stats.Threads)),
+			influxField("open_fds", float64(stats.FDs)),
+			influxField("read_bytes_per_sec", stats.ReadBytes),
+			influxField("write_bytes_per_sec", stats.WriteBytes),
+			influxField("ctx_switches_per_sec", stats.CtxSwitches),
+		})
+	}
+
+	for _, cg := range sortedKeys(snapshot.Cgroups) {
+		stats := snapshot.Cgroups[cg]
+		cgTag := hostTag + ",cgroup=" + escapeInfluxTag(cg)
+		fields := []string{
+			influxField("cpu", stats.CPU),
+			influxField("cpu_user", stats.CPUUser),
+			influxField("cpu_system", stats.CPUSystem),
+			influxField("throttled_percent", stats.ThrottledPercent),
+			influxField("throttled_ms_per_sec", stats.ThrottledTime),
+			influxField("memory_bytes", float64(stats.MemoryCurrent)),
+		}
+		if stats.MemoryMax > 0 {
+			fields = append(fields,
+				influxField("memory_max_bytes", float64(stats.MemoryMax)),
+				influxField("memory_used_percent", stats.MemoryPercent))
+		}
+		fields = append(fields, influxField("pids", float64(stats.PIDs)))
+		writeLine("unostat_cgroup", cgTag, fields)
+
+		for _, dev := range sortedKeys(stats.IO) {
+			devIO := stats.IO[dev]
+			writeLine("unostat_cgroup_io", cgTag+",device="+escapeInfluxTag(dev), []string{
+				influxField("read_bytes_per_sec", devIO.ReadBytes),
+				influxField("write_bytes_per_sec", devIO.WriteBytes),
+				influxField("read_iops", devIO.ReadIOPS),
+				influxField("write_iops", devIO.WriteIOPS),
+			})
+		}
+	}
+
+	for _, resource := range sortedKeys(snapshot.Pressure) {
+		stats := snapshot.Pressure[resource]
+		fields := []string{
+			influxField("some", stats.Some),
+			influxField("some_avg10", stats.SomeAvg10),
+			influxField("some_avg60", stats.SomeAvg60),
+		}
+		if stats.Full >= 0 {
+			fields = append(fields,
+				influxField("full", stats.Full),
+				influxField("full_avg10", stats.FullAvg10),
+				influxField("full_avg60", stats.FullAvg60))
+		}
+		writeLine("unostat_pressure", hostTag+",resource="+escapeInfluxTag(resource), fields)
+	}
+
+	if system := snapshot.System; system != nil {
+		writeLine("unostat_system", hostTag, []string{
+			influxField("load1", system.Load1),
+			influxField("load5", system.Load5),
+			influxField("load15", system.Load15),
+			influxField("ctx_switches_per_sec", system.ContextSwitches),
+			influxField("interrupts_per_sec", system.Interrupts),
+			influxField("forks_per_sec", system.Forks),
+			influxField("procs_running", float64(system.ProcsRunning)),
+			influxField("procs_blocked", float64(system.ProcsBlocked)),
+		})
+	}
+
+	if netstat := snapshot.Netstat; netstat != nil {
+		fields := make([]string, 0, len(metrics.TCPStates)+7)
+		for _, state := range metrics.TCPStates {
+			fields = append(fields, influxField("tcp_"+strings.ToLower(state), float64(netstat.TCPStates[state])))
+		}
+		fields = append(fields,
+			influxField("tcp_active_opens_per_sec", netstat.ActiveOpens),
+			influxField("tcp_passive_opens_per_sec", netstat.PassiveOpens),
+			influxField("tcp_retrans_segs_per_sec", netstat.RetransSegs),
+			influxField("tcp_listen_overflows_per_sec", netstat.ListenOverflows),
+			influxField("tcp_listen_drops_per_sec", netstat.ListenDrops),
+			influxField("udp_in_errors_per_sec", netstat.UDPInErrors),
+			influxField("udp_rcvbuf_errors_per_sec", netstat.UDPRcvbufErrors))
+		writeLine("unostat_netstat", hostTag, fields)
+	}
+
+	// Only flag the snapshots with re-baselined devices
+	if n := len(snapshot.CounterResets); n > 0 {
+		writeLine("unostat_sample", hostTag, []string{influxField("counter_resets", float64(n))})
+	}
+
+	return sb.String()
+}
+
+// influxField formats a float field as key=value.
+func influxField(key string, value float64) string {
+	return key + "=" + strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// influxTagEscaper escapes tag keys and values per the line protocol.
+var influxTagEscaper = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `)
+
+// escapeInfluxTag escapes a tag value. Empty values are replaced as they are invalid.
+func escapeInfluxTag(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return influxTagEscaper.Replace(v)
+}
+
+// redactURL strips credentials from a URL for logging.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
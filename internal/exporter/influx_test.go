@@ -0,0 +1,298 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func TestFormatLineProtocol(t *testing.T) {
+	snapshot := &metrics.Snapshot{
+		Timestamp: time.Unix(1700000000, 5),
+		CPU:       45.5,
+		CPUWait:   -1,
+		Memory:    60,
+		Disks:     map[string]metrics.DiskStats{"C:": {Utilization: 10.5, Await: 5, IOPS: 100}},
+		Networks:  map[string]metrics.NetStats{"Wi Fi": {Bandwidth: 1e7}},
+	}
+
+	got := formatLineProtocol(snapshot, "host,1")
+	want := "unostat_cpu,host=host\\,1 utilization=45.5,user=0,system=0,irq=0,softirq=0,steal=0,guest=0 1700000000000000005\n" +
+		"unostat_memory,host=host\\,1 utilization=60,available_bytes=0,cached_bytes=0,buffers_bytes=0,dirty_bytes=0,writeback_bytes=0," +
+		"slab_bytes=0,swap_used_bytes=0,swap_total_bytes=0,swap_in_pages_per_sec=0,swap_out_pages_per_sec=0 1700000000000000005\n" +
+		"unostat_disk,host=host\\,1,device=C: utilization=10.5,await_ms=5,iops=100," +
+		"read_iops=0,write_iops=0,read_bytes_per_sec=0,write_bytes_per_sec=0,read_await_ms=0,write_await_ms=0,avg_request_bytes=0,avg_queue_size=0 1700000000000000005\n" +
+		"unostat_network,host=host\\,1,interface=Wi\\ Fi bandwidth_bps=10000000," +
+		"rx_bps=0,tx_bps=0,rx_packets_per_sec=0,tx_packets_per_sec=0,errin_per_sec=0,errout_per_sec=0,dropin_per_sec=0,dropout_per_sec=0 1700000000000000005\n"
+
+	if got != want {
+		t.Errorf("formatLineProtocol() =\n%s\nwant\n%s", got, want)
+	}
+
+	snapshot.System = &metrics.SystemStats{Load1: 0.5, Load5: 0.25, Load15: 0.1, ContextSwitches: 900, ProcsRunning: 2}
+	if got := formatLineProtocol(snapshot, "vm"); !strings.Contains(got, "unostat_system,host=vm load1=0.5,load5=0.25,load15=0.1,"+
+		"ctx_switches_per_sec=900,interrupts_per_sec=0,forks_per_sec=0,procs_running=2,procs_blocked=0 1700000000000000005\n") {
+		t.Errorf("formatLineProtocol() with system stats =\n%s", got)
+	}
+
+	snapshot.CounterResets = []string{"disk/C:"}
+	if got := formatLineProtocol(snapshot, "vm"); !strings.HasSuffix(got, "unostat_sample,host=vm counter_resets=1 1700000000000000005\n") {
+		t.Errorf("formatLineProtocol() with counter resets =\n%s", got)
+	}
+}
+
+func TestInfluxExporter_FileMode(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{OutputPath: filepath.Join(tempDir, "out.csv")}
+
+	exp, err := NewInfluxExporter(cfg, logger)
+	if err != nil {
+		t.Fatalf("NewInfluxExporter() error = %v", err)
+	}
+	if err := exp.Write(&metrics.Snapshot{Timestamp: time.Unix(1, 0), CPU: 1, CPUWait: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "out.lp"))
+	if err != nil {
+		t.Fatalf("line protocol file missing: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "unostat_cpu,host=") || !strings.Contains(string(data), "iowait=2,") {
+		t.Errorf("unexpected file content:\n%s", data)
+	}
+}
+
+// influxStub is an httptest stand-in for the write endpoint.
+type influxStub struct {
+	mu       sync.Mutex
+	status   int // Error status to answer with, 0 accepts the write
+	attempts int
+	bodies   []string
+	header   http.Header
+	query    string
+}
+
+func (s *influxStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.status != 0 {
+		http.Error(w, http.StatusText(s.status), s.status)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(body))
+	s.header = r.Header.Clone()
+	s.query = r.URL.RawQuery
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestInfluxExporter_HTTPWithSpool(t *testing.T) {
+	origBackoff := influxInitialBackoff
+	influxInitialBackoff = time.Millisecond
+	defer func() { influxInitialBackoff = origBackoff }()
+
+	stub := &influxStub{status: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		OutputPath:   filepath.Join(tempDir, "out.csv"),
+		InfluxURL:    srv.URL,
+		InfluxToken:  "secret",
+		InfluxOrg:    "perf",
+		InfluxBucket: "unostat",
+	}
+
+	exp, err := NewInfluxExporter(cfg, logger)
+	if err != nil {
+		t.Fatalf("NewInfluxExporter() error = %v", err)
+	}
+
+	// Endpoint down: batch goes to the spool
+	if err := exp.Write(&metrics.Snapshot{Timestamp: time.Unix(1, 0), CPU: 1, CPUWait: -1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Flush(); err == nil {
+		t.Error("Flush() expected error while endpoint is down")
+	}
+	spoolPath := filepath.Join(tempDir, "out.spool.lp")
+	if data, err := os.ReadFile(spoolPath); err != nil || !strings.Contains(string(data), "unostat_cpu,host=vm utilization=1,") {
+		t.Fatalf("spool file = %q, %v; want spooled batch", data, err)
+	}
+
+	// Endpoint back: spool is replayed before the new batch
+	stub.mu.Lock()
+	stub.status = 0
+	stub.mu.Unlock()
+
+	if err := exp.Write(&metrics.Snapshot{Timestamp: time.Unix(2, 0), CPU: 2, CPUWait: -1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(spoolPath); !os.IsNotExist(err) {
+		t.Error("spool file should be removed after a successful replay")
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	if len(stub.bodies) != 2 {
+		t.Fatalf("endpoint received %d requests, want 2", len(stub.bodies))
+	}
+	if !strings.Contains(stub.bodies[0], "unostat_cpu,host=vm utilization=1,") || !strings.Contains(stub.bodies[1], "unostat_cpu,host=vm utilization=2,") {
+		t.Errorf("unexpected request order: %q", stub.bodies)
+	}
+	if got := stub.header.Get("Authorization"); got != "Token secret" {
+		t.Errorf("Authorization = %q, want Token secret", got)
+	}
+	for _, param := range []string{"org=perf", "bucket=unostat", "precision=ns"} {
+		if !strings.Contains(stub.query, param) {
+			t.Errorf("query %q missing %s", stub.query, param)
+		}
+	}
+}
+
+func TestInfluxExporter_HTTPRejectedBatch(t *testing.T) {
+	stub := &influxStub{status: http.StatusBadRequest}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	tempDir := t.TempDir()
+	spoolPath := filepath.Join(tempDir, "out.spool.lp")
+	if err := os.WriteFile(spoolPath, []byte("unostat_cpu,host=vm utilization=1 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exp, err := NewInfluxExporter(&config.Config{OutputPath: filepath.Join(tempDir, "out.csv"), InfluxURL: srv.URL}, logger)
+	if err != nil {
+		t.Fatalf("NewInfluxExporter() error = %v", err)
+	}
+
+	// Neither the spooled lines nor the new batch are retried or spooled again
+	if err := exp.Write(&metrics.Snapshot{Timestamp: time.Unix(2, 0), CPU: 2, CPUWait: -1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Flush(); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("Flush() error = %v, want rejected batch", err)
+	}
+	if _, err := os.Stat(spoolPath); !os.IsNotExist(err) {
+		t.Error("rejected spooled lines should be dropped")
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	if stub.attempts != 2 {
+		t.Errorf("endpoint received %d requests, want 2", stub.attempts)
+	}
+}
+
+func TestInfluxExporter_BackoffStopsOnShutdown(t *testing.T) {
+	origBackoff := influxInitialBackoff
+	influxInitialBackoff = time.Hour
+	defer func() { influxInitialBackoff = origBackoff }()
+
+	stub := &influxStub{status: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exp, err := NewInfluxExporter(&config.Config{OutputPath: filepath.Join(tempDir, "out.csv"), InfluxURL: srv.URL}, logger)
+	if err != nil {
+		t.Fatalf("NewInfluxExporter() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := exp.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	if err := exp.Write(&metrics.Snapshot{Timestamp: time.Unix(1, 0), CPU: 1, CPUWait: -1}); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- exp.Flush() }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "spooled") {
+			t.Errorf("Flush() error = %v, want spooled batch", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Flush() kept backing off after shutdown")
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	if stub.attempts != 1 {
+		t.Errorf("endpoint received %d requests, want 1", stub.attempts)
+	}
+}
+
+func TestBuildInfluxWriteURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"http://localhost:8086", "http://localhost:8086/api/v2/write?precision=ns", false},
+		{"http://localhost:8086/", "http://localhost:8086/api/v2/write?precision=ns", false},
+		{"https://proxy/api/v2/write", "https://proxy/api/v2/write?precision=ns", false},
+		{"localhost:8086", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := buildInfluxWriteURL(&config.Config{InfluxURL: tt.url})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildInfluxWriteURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("buildInfluxWriteURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,472 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func init() {
+	Register("jsonl", func(cfg *config.Config, logger *slog.Logger) (Exporter, error) {
+		return NewJSONLExporter(cfg, logger)
+	})
+}
+
+// jsonlRecord is the JSON Lines representation of a snapshot.
+type jsonlRecord struct {
+	Timestamp   string                     `json:"timestamp"`
+	CPU         float64                    `json:"cpu"`
+	CPUWait     *float64                   `json:"cpu_wait"` // null if N/A
+	CPUTimes    jsonlCPUTimes              `json:"cpu_times"`
+	MaxCore     *float64                   `json:"cpu_max_core,omitempty"` // Busiest core, per-CPU mode only
+	Cores       map[string]jsonlCore       `json:"cores,omitempty"`        // Per-CPU mode only
+	Memory      float64                    `json:"memory"`
+	MemDetail   jsonlMemory                `json:"memory_detail"`
+	Disks       map[string]jsonlDisk       `json:"disks"`
+	Networks    map[string]jsonlNetwork    `json:"networks"`
+	Filesystems map[string]jsonlFilesystem `json:"filesystems,omitempty"`
+	Processes   map[string]jsonlProcess    `json:"processes,omitempty"`
+	Cgroups     map[string]jsonlCgroup     `json:"cgroups,omitempty"`
+	Pressure    map[string]jsonlPressure   `json:"pressure,omitempty"`
+	System      *jsonlSystem               `json:"system,omitempty"`
+	Netstat     *jsonlNetstat              `json:"netstat,omitempty"`
+	Resets      []string                   `json:"counter_resets,omitempty"` // Sources re-baselined after a counter reset
+}
+
+// jsonlCPUTimes is the JSON Lines representation of the CPU time breakdown.
+type jsonlCPUTimes struct {
+	User    float64 `json:"user"`
+	System  float64 `json:"system"`
+	Irq     float64 `json:"irq"`
+	SoftIrq float64 `json:"softirq"`
+	Steal   float64 `json:"steal"`
+	Guest   float64 `json:"guest"`
+}
+
+// jsonlCore is the JSON Lines representation of a logical core's metrics.
+type jsonlCore struct {
+	Utilization float64  `json:"utilization"`
+	IOWait      *float64 `json:"iowait"` // null if N/A
+}
+
+// jsonlMemory is the JSON Lines representation of the memory breakdown and swap activity.
+type jsonlMemory struct {
+	Available uint64  `json:"available_bytes"`
+	Cached    uint64  `json:"cached_bytes"`
+	Buffers   uint64  `json:"buffers_bytes"`
+	Dirty     uint64  `json:"dirty_bytes"`
+	WriteBack uint64  `json:"writeback_bytes"`
+	Slab      uint64  `json:"slab_bytes"`
+	SwapUsed  uint64  `json:"swap_used_bytes"`
+	SwapTotal uint64  `json:"swap_total_bytes"`
+	SwapIn    float64 `json:"swap_in_pages_per_sec"`
+	SwapOut   float64 `json:"swap_out_pages_per_sec"`
+}
+
+// jsonlDisk is the JSON Lines representation of a disk's metrics.
+type jsonlDisk struct {
+	Utilization float64 `json:"utilization"`
+	Await       float64 `json:"await_ms"`
+	IOPS        float64 `json:"iops"`
+
+	ReadIOPS        float64 `json:"read_iops"`
+	WriteIOPS       float64 `json:"write_iops"`
+	ReadThroughput  float64 `json:"read_bytes_per_sec"`
+	WriteThroughput float64 `json:"write_bytes_per_sec"`
+	ReadAwait       float64 `json:"read_await_ms"`
+	WriteAwait      float64 `json:"write_await_ms"`
+	AvgRequestSize  float64 `json:"avg_request_bytes"`
+	QueueDepth      float64 `json:"avg_queue_size"`
+}
+
+// jsonlNetwork is the JSON Lines representation of an interface's metrics.
+type jsonlNetwork struct {
+	Bandwidth float64 `json:"bandwidth_bps"`
+
+	RxBandwidth float64 `json:"rx_bps"`
+	TxBandwidth float64 `json:"tx_bps"`
+	RxPackets   float64 `json:"rx_packets_per_sec"`
+	TxPackets   float64 `json:"tx_packets_per_sec"`
+	ErrIn       float64 `json:"errin_per_sec"`
+	ErrOut      float64 `json:"errout_per_sec"`
+	DropIn      float64 `json:"dropin_per_sec"`
+	DropOut     float64 `json:"dropout_per_sec"`
+}
+
+// jsonlFilesystem is the JSON Lines representation of a filesystem's usage.
+type jsonlFilesystem struct {
+	Device string `json:"device"`
+	Fstype string `json:"fstype"`
+
+	Total       uint64  `json:"total_bytes"`
+	Used        uint64  `json:"used_bytes"`
+	Free        uint64  `json:"free_bytes"`
+	UsedPercent float64 `json:"used_percent"`
+
+	InodesTotal       uint64  `json:"inodes_total"`
+	InodesUsed        uint64  `json:"inodes_used"`
+	InodesFree        uint64  `json:"inodes_free"`
+	InodesUsedPercent float64 `json:"inodes_used_percent"`
+}
+
+// jsonlProcess is the JSON Lines representation of the processes matched by a selector.
+type jsonlProcess struct {
+	Count       int     `json:"count"`
+	CPU         float64 `json:"cpu"`
+	RSS         uint64  `json:"rss_bytes"`
+	Threads     int64   `json:"threads"`
+	FDs         int64   `json:"open_fds"`
+	ReadBytes   float64 `json:"read_bytes_per_sec"`
+	WriteBytes  float64 `json:"write_bytes_per_sec"`
+	CtxSwitches float64 `json:"ctx_switches_per_sec"`
+}
+
+// jsonlCgroup is the JSON Lines representation of a cgroup.
+type jsonlCgroup struct {
+	CPU              float64                  `json:"cpu"`
+	CPUUser          float64                  `json:"cpu_user"`
+	CPUSystem        float64                  `json:"cpu_system"`
+	ThrottledPercent float64                  `json:"throttled_percent"`
+	ThrottledTime    float64                  `json:"throttled_ms_per_sec"`
+	MemoryCurrent    uint64                   `json:"memory_bytes"`
+	MemoryMax        *uint64                  `json:"memory_max_bytes"`    // null if unlimited
+	MemoryPercent    *float64                 `json:"memory_used_percent"` // null if unlimited
+	PIDs             uint64                   `json:"pids"`
+	IO               map[string]jsonlCgroupIO `json:"io,omitempty"`
+}
+
+// jsonlCgroupIO is the JSON Lines representation of the I/O of a cgroup on one device.
+type jsonlCgroupIO struct {
+	ReadBytes  float64 `json:"read_bytes_per_sec"`
+	WriteBytes float64 `json:"write_bytes_per_sec"`
+	ReadIOPS   float64 `json:"read_iops"`
+	WriteIOPS  float64 `json:"write_iops"`
+}
+
+// jsonlPressure is the JSON Lines representation of the PSI of a resource.
+type jsonlPressure struct {
+	Some      float64  `json:"some"`
+	Full      *float64 `json:"full"` // null if N/A
+	SomeAvg10 float64  `json:"some_avg10"`
+	SomeAvg60 float64  `json:"some_avg60"`
+	FullAvg10 *float64 `json:"full_avg10"` // null if N/A
+	FullAvg60 *float64 `json:"full_avg60"` // null if N/A
+}
+
+// jsonlSystem is the JSON Lines representation of the kernel activity counters.
+type jsonlSystem struct {
+	Load1           float64 `json:"load1"`
+	Load5           float64 `json:"load5"`
+	Load15          float64 `json:"load15"`
+	ContextSwitches float64 `json:"ctx_switches_per_sec"`
+	Interrupts      float64 `json:"interrupts_per_sec"`
+	Forks           float64 `json:"forks_per_sec"`
+	ProcsRunning    uint64  `json:"procs_running"`
+	ProcsBlocked    uint64  `json:"procs_blocked"`
+}
+
+// jsonlNetstat is the JSON Lines representation of the TCP/UDP protocol statistics.
+type jsonlNetstat struct {
+	TCPStates       map[string]uint64 `json:"tcp_states"`
+	ActiveOpens     float64           `json:"tcp_active_opens_per_sec"`
+	PassiveOpens    float64           `json:"tcp_passive_opens_per_sec"`
+	RetransSegs     float64           `json:"tcp_retrans_segs_per_sec"`
+	ListenOverflows float64           `json:"tcp_listen_overflows_per_sec"`
+	ListenDrops     float64           `json:"tcp_listen_drops_per_sec"`
+	UDPInErrors     float64           `json:"udp_in_errors_per_sec"`
+	UDPRcvbufErrors float64           `json:"udp_rcvbuf_errors_per_sec"`
+}
+
+// JSONLExporter exports metrics as JSON Lines, one self-describing object per snapshot.
+// Unlike the CSV format there is no header, so devices appearing mid-run are recorded as-is.
+type JSONLExporter struct {
+	config      *config.Config
+	file        *os.File
+	bufWriter   *bufio.Writer
+	logger      *slog.Logger
+	location    *time.Location // Timezone location for timestamps
+	currentSize int64          // Current file size in bytes
+	basePath    string         // Base output path
+	rotator     *rotator       // Rotation and retention policy
+}
+
+// jsonlOutputPath derives the JSON Lines output path from the configured CSV output path.
+func jsonlOutputPath(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".jsonl"
+}
+
+// NewJSONLExporter creates a new JSON Lines exporter instance.
+// The output file is the configured output path with a .jsonl extension.
+func NewJSONLExporter(cfg *config.Config, logger *slog.Logger) (*JSONLExporter, error) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s': %w", cfg.Timezone, err)
+	}
+
+	path := jsonlOutputPath(cfg.OutputPath)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output file: %w", err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close() // Close if stat fails, ignore error as we're already failing
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return &JSONLExporter{
+		config:      cfg,
+		file:        file,
+		bufWriter:   bufio.NewWriterSize(file, 8192), // 8KB buffer
+		logger:      logger,
+		location:    loc,
+		currentSize: stat.Size(),
+		basePath:    path,
+		rotator:     newRotator(cfg, path, loc),
+	}, nil
+}
+
+// Name returns the sink name.
+func (e *JSONLExporter) Name() string {
+	return "jsonl"
+}
+
+// Start logs the exporter settings; the output file is already opened by NewJSONLExporter.
+func (e *JSONLExporter) Start(_ context.Context) error {
+	e.logger.Info("Starting JSONL exporter", "output", e.basePath, "timezone", e.config.Timezone)
+	e.rotator.prune(e.file.Name(), e.logger)
+	return nil
+}
+
+// Write appends a snapshot as a single JSON line.
+func (e *JSONLExporter) Write(snapshot *metrics.Snapshot) error {
+	line, err := json.Marshal(e.buildRecord(snapshot))
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	line = append(line, '\n')
+
+	// Check for rotation before writing to avoid going too much over the limit
+	if due, reason := e.rotator.due(e.currentSize, snapshot.Timestamp); due {
+		e.logger.Debug("Rotation due", "reason", reason)
+		if err := e.rotateFile(snapshot.Timestamp); err != nil {
+			e.logger.Error("Failed to rotate file", "error", err)
+		}
+	}
+
+	n, err := e.bufWriter.Write(line)
+	e.currentSize += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write line: %w", err)
+	}
+	return nil
+}
+
+// buildRecord converts a snapshot to its JSON Lines representation.
+func (e *JSONLExporter) buildRecord(snapshot *metrics.Snapshot) jsonlRecord {
+	record := jsonlRecord{
+		Timestamp: snapshot.Timestamp.In(e.location).Format(time.RFC3339),
+		CPU:       snapshot.CPU,
+		CPUTimes:  jsonlCPUTimes(snapshot.CPUTimes),
+		Memory:    snapshot.Memory,
+		MemDetail: jsonlMemory(snapshot.MemDetail),
+		Disks:     make(map[string]jsonlDisk, len(snapshot.Disks)),
+		Networks:  make(map[string]jsonlNetwork, len(snapshot.Networks)),
+		Resets:    snapshot.CounterResets,
+	}
+
+	if snapshot.CPUWait >= 0 {
+		cpuWait := snapshot.CPUWait
+		record.CPUWait = &cpuWait
+	}
+
+	if _, maxUtil, ok := snapshot.MaxCore(); ok {
+		record.MaxCore = &maxUtil
+		record.Cores = make(map[string]jsonlCore, len(snapshot.Cores))
+		for core, stats := range snapshot.Cores {
+			c := jsonlCore{Utilization: stats.Utilization}
+			if stats.IOWait >= 0 {
+				iowait := stats.IOWait
+				c.IOWait = &iowait
+			}
+			record.Cores[core] = c
+		}
+	}
+
+	for device, stats := range snapshot.Disks {
+		record.Disks[device] = jsonlDisk(stats)
+	}
+
+	for iface, stats := range snapshot.Networks {
+		record.Networks[iface] = jsonlNetwork(stats)
+	}
+
+	if len(snapshot.Filesystems) > 0 {
+		record.Filesystems = make(map[string]jsonlFilesystem, len(snapshot.Filesystems))
+		for mount, stats := range snapshot.Filesystems {
+			record.Filesystems[mount] = jsonlFilesystem(stats)
+		}
+	}
+
+	if len(snapshot.Processes) > 0 {
+		record.Processes = make(map[string]jsonlProcess, len(snapshot.Processes))
+		for proc, stats := range snapshot.Processes {
+			record.Processes[proc] = jsonlProcess(stats)
+		}
+	}
+
+	if len(snapshot.Cgroups) > 0 {
+		record.Cgroups = make(map[string]jsonlCgroup, len(snapshot.Cgroups))
+		for cg, stats := range snapshot.Cgroups {
+			rec := jsonlCgroup{
+				CPU:              stats.CPU,
+				CPUUser:          stats.CPUUser,
+				CPUSystem:        stats.CPUSystem,
+				ThrottledPercent: stats.ThrottledPercent,
+				ThrottledTime:    stats.ThrottledTime,
+				MemoryCurrent:    stats.MemoryCurrent,
+				PIDs:             stats.PIDs,
+			}
+			if stats.MemoryMax > 0 {
+				rec.MemoryMax = &stats.MemoryMax
+				rec.MemoryPercent = &stats.MemoryPercent
+			}
+			if len(stats.IO) > 0 {
+				rec.IO = make(map[string]jsonlCgroupIO, len(stats.IO))
+				for dev, devIO := range stats.IO {
+					rec.IO[dev] = jsonlCgroupIO(devIO)
+				}
+			}
+			record.Cgroups[cg] = rec
+		}
+	}
+
+	if snapshot.System != nil {
+		system := jsonlSystem(*snapshot.System)
+		record.System = &system
+	}
+
+	if snapshot.Netstat != nil {
+		netstat := jsonlNetstat(*snapshot.Netstat)
+		record.Netstat = &netstat
+	}
+
+	if len(snapshot.Pressure) > 0 {
+		record.Pressure = make(map[string]jsonlPressure, len(snapshot.Pressure))
+		for resource, stats := range snapshot.Pressure {
+			record.Pressure[resource] = jsonlPressure{
+				Some:      stats.Some,
+				Full:      optionalPercent(stats.Full),
+				SomeAvg10: stats.SomeAvg10,
+				SomeAvg60: stats.SomeAvg60,
+				FullAvg10: optionalPercent(stats.FullAvg10),
+				FullAvg60: optionalPercent(stats.FullAvg60),
+			}
+		}
+	}
+
+	return record
+}
+
+// optionalPercent returns nil for a negative (not available) percentage.
+func optionalPercent(value float64) *float64 {
+	if value < 0 {
+		return nil
+	}
+	return &value
+}
+
+// Flush flushes the buffered data to disk.
+func (e *JSONLExporter) Flush() error {
+	if err := e.bufWriter.Flush(); err != nil {
+		return fmt.Errorf("buffer writer error: %w", err)
+	}
+	e.logger.Debug("Flushed to disk", "output", e.file.Name())
+	return nil
+}
+
+// Close closes the JSONL exporter and flushes remaining data.
+func (e *JSONLExporter) Close() error {
+	e.logger.Info("Closing JSONL exporter")
+
+	if err := e.Flush(); err != nil {
+		e.logger.Error("Final flush failed", "error", err)
+	}
+
+	if err := e.file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	// Wait for background compression of rotated files
+	e.rotator.wait()
+
+	e.logger.Info("JSONL exporter closed")
+	return nil
+}
+
+// rotateFile continues in the next file, closes the current one and applies the retention policy.
+// start is the timestamp of the first record of the new file. The next file is opened first,
+// so a failed rotation keeps writing to the current file.
+func (e *JSONLExporter) rotateFile(start time.Time) error {
+	e.logger.Info("Rotating output file", "current_size", e.currentSize)
+
+	if err := e.Flush(); err != nil {
+		return fmt.Errorf("flush before rotate failed: %w", err)
+	}
+
+	newPath := e.rotator.nextPath(start)
+	file, err := os.OpenFile(newPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open new rotated file: %w", err)
+	}
+
+	oldFile := e.file
+	e.file = file
+	e.bufWriter = bufio.NewWriterSize(file, 8192)
+	e.currentSize = 0
+
+	// The buffered data is already flushed, so the rotation stands even if close fails
+	if err := oldFile.Close(); err != nil {
+		e.logger.Warn("Failed to close rotated file", "path", oldFile.Name(), "error", err)
+	} else {
+		e.rotator.compressClosed(oldFile.Name(), e.logger)
+	}
+
+	e.logger.Info("File rotated successfully", "new_path", newPath)
+	e.rotator.prune(newPath, e.logger)
+	return nil
+}
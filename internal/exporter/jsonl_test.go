@@ -0,0 +1,194 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func TestJSONLExporter_Write(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		OutputPath: filepath.Join(tempDir, "export.csv"),
+		Timezone:   "Asia/Ho_Chi_Minh",
+	}
+
+	exp, err := NewJSONLExporter(cfg, logger)
+	if err != nil {
+		t.Fatalf("NewJSONLExporter() error = %v", err)
+	}
+
+	now := time.Date(2023, 10, 26, 12, 0, 0, 0, time.UTC)
+	snapshots := []*metrics.Snapshot{
+		{
+			Timestamp: now,
+			CPU:       45.5, CPUWait: 2.5, Memory: 60,
+			Disks:    map[string]metrics.DiskStats{"sda": {Utilization: 10.5, Await: 5, IOPS: 100}},
+			Networks: map[string]metrics.NetStats{"eth0": {Bandwidth: 10_000_000}},
+			System:   &metrics.SystemStats{Load1: 0.5, Forks: 12, ProcsBlocked: 1},
+		},
+		{
+			Timestamp: now.Add(time.Second),
+			CPU:       10, CPUWait: -1, Memory: 20,
+			// A disk appearing mid-run must be recorded
+			Disks: map[string]metrics.DiskStats{"sda": {}, "sdb": {IOPS: 7}},
+		},
+	}
+	for _, s := range snapshots {
+		if err := exp.Write(s); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(tempDir, "export.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to open output file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var records []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	if got := records[0]["timestamp"]; got != "2023-10-26T19:00:00+07:00" {
+		t.Errorf("timestamp = %v, want 2023-10-26T19:00:00+07:00", got)
+	}
+	if got := records[0]["cpu_wait"]; got != 2.5 {
+		t.Errorf("cpu_wait = %v, want 2.5", got)
+	}
+	if got, ok := records[1]["cpu_wait"]; !ok || got != nil {
+		t.Errorf("cpu_wait = %v (present %v), want null", got, ok)
+	}
+
+	if system, ok := records[0]["system"].(map[string]any); !ok || system["load1"] != 0.5 || system["forks_per_sec"] != 12.0 || system["procs_blocked"] != 1.0 {
+		t.Errorf("system = %v, want load1 0.5, forks_per_sec 12, procs_blocked 1", records[0]["system"])
+	}
+	if _, ok := records[1]["system"]; ok {
+		t.Error("system should be omitted when not collected")
+	}
+
+	disks, ok := records[1]["disks"].(map[string]any)
+	if !ok {
+		t.Fatalf("disks has unexpected type %T", records[1]["disks"])
+	}
+	sdb, ok := disks["sdb"].(map[string]any)
+	if !ok || sdb["iops"] != 7.0 {
+		t.Errorf("disks.sdb = %v, want iops 7", disks["sdb"])
+	}
+
+	nets, ok := records[0]["networks"].(map[string]any)
+	if !ok {
+		t.Fatalf("networks has unexpected type %T", records[0]["networks"])
+	}
+	if eth0, ok := nets["eth0"].(map[string]any); !ok || eth0["bandwidth_bps"] != 1e7 {
+		t.Errorf("networks.eth0 = %v, want bandwidth_bps 1e7", nets["eth0"])
+	}
+}
+
+func TestJSONLExporter_FileRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		OutputPath: filepath.Join(tempDir, "rotate.csv"),
+		Timezone:   "UTC",
+	}
+
+	exp, err := NewJSONLExporter(cfg, logger)
+	if err != nil {
+		t.Fatalf("NewJSONLExporter() error = %v", err)
+	}
+
+	// Manually set size to trigger rotation
+	exp.currentSize = config.DefaultMaxOutputFileSize + 1
+
+	if err := exp.Write(&metrics.Snapshot{Timestamp: time.Now(), CPUWait: -1}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "rotate_1.jsonl"))
+	if err != nil {
+		t.Fatalf("Rotated file does not exist: %v", err)
+	}
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		t.Errorf("rotated file should contain one full line, got %q", data)
+	}
+}
+
+func TestJSONLExporter_FailedRotationKeepsFile(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "out")
+	if err := os.Mkdir(outDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exp, err := NewJSONLExporter(&config.Config{OutputPath: filepath.Join(outDir, "rotate.csv"), Timezone: "UTC"}, logger)
+	if err != nil {
+		t.Fatalf("NewJSONLExporter() error = %v", err)
+	}
+	current := exp.file
+
+	// The next file cannot be created once its directory is gone
+	if err := os.RemoveAll(outDir); err != nil {
+		t.Fatal(err)
+	}
+	exp.currentSize = config.DefaultMaxOutputFileSize + 1
+	if err := exp.Write(&metrics.Snapshot{Timestamp: time.Now(), CPUWait: -1}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if exp.file != current {
+		t.Error("failed rotation should keep the current file")
+	}
+	if err := exp.Flush(); err != nil {
+		t.Errorf("Flush() after failed rotation error = %v", err)
+	}
+	if err := exp.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
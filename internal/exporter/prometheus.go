@@ -0,0 +1,587 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func init() {
+	Register("prometheus", func(cfg *config.Config, logger *slog.Logger) (Exporter, error) {
+		return NewPrometheusExporter(cfg, logger), nil
+	})
+}
+
+// prometheusContentType is the content type of the text exposition format.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusExporter serves the latest snapshot on an HTTP /metrics endpoint
+// in the Prometheus text exposition format. Scrapes never trigger a new sample;
+// they always return the most recent snapshot produced by the collector manager.
+type PrometheusExporter struct {
+	listenAddr string
+	logger     *slog.Logger
+	server     *http.Server
+	listener   net.Listener
+
+	mu     sync.RWMutex
+	latest *metrics.Snapshot
+}
+
+// NewPrometheusExporter creates a new Prometheus exporter instance.
+// The listen address defaults to config.DefaultPrometheusListen.
+func NewPrometheusExporter(cfg *config.Config, logger *slog.Logger) *PrometheusExporter {
+	addr := cfg.PrometheusListen
+	if addr == "" {
+		addr = config.DefaultPrometheusListen
+	}
+	return &PrometheusExporter{
+		listenAddr: addr,
+		logger:     logger,
+	}
+}
+
+// Name returns the sink name.
+func (e *PrometheusExporter) Name() string {
+	return "prometheus"
+}
+
+// Start binds the listener and serves /metrics in the background.
+func (e *PrometheusExporter) Start(_ context.Context) error {
+	ln, err := net.Listen("tcp", e.listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", e.listenAddr, err)
+	}
+	e.listener = ln
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.handleMetrics)
+
+	e.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := e.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			e.logger.Error("Prometheus endpoint stopped", "error", err)
+		}
+	}()
+
+	e.logger.Info("Starting Prometheus exporter", "address", ln.Addr().String(), "path", "/metrics")
+	return nil
+}
+
+// Addr returns the address the endpoint listens on, or an empty string before Start.
+func (e *PrometheusExporter) Addr() string {
+	if e.listener == nil {
+		return ""
+	}
+	return e.listener.Addr().String()
+}
+
+// Write stores the snapshot as the one returned by subsequent scrapes.
+func (e *PrometheusExporter) Write(snapshot *metrics.Snapshot) error {
+	e.mu.Lock()
+	e.latest = snapshot
+	e.mu.Unlock()
+	return nil
+}
+
+// Flush is a no-op; snapshots are served from memory.
+func (e *PrometheusExporter) Flush() error {
+	return nil
+}
+
+// Close shuts down the HTTP endpoint.
+func (e *PrometheusExporter) Close() error {
+	if e.server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := e.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down Prometheus endpoint: %w", err)
+	}
+
+	e.logger.Info("Prometheus exporter closed")
+	return nil
+}
+
+// handleMetrics writes the latest snapshot in text exposition format.
+func (e *PrometheusExporter) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	e.mu.RLock()
+	snapshot := e.latest
+	e.mu.RUnlock()
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	if err := writePrometheusText(w, snapshot); err != nil {
+		e.logger.Warn("Failed to write metrics response", "error", err)
+	}
+}
+
+// promWriter accumulates the text exposition output and remembers the first write error.
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+// family writes the HELP and TYPE lines of a metric family.
+func (p *promWriter) family(name, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// sample writes a single sample line with an optional label.
+func (p *promWriter) sample(name, label, labelValue string, value float64) {
+	if label == "" {
+		p.printf("%s %s\n", name, formatPromValue(value))
+		return
+	}
+	p.printf("%s{%s=\"%s\"} %s\n", name, label, escapePromLabel(labelValue), formatPromValue(value))
+}
+
+func (p *promWriter) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+// writePrometheusText renders a snapshot in the Prometheus text exposition format.
+// A nil snapshot (nothing collected yet) produces no samples.
+func writePrometheusText(w io.Writer, snapshot *metrics.Snapshot) error {
+	p := &promWriter{w: w}
+	if snapshot == nil {
+		return nil
+	}
+
+	p.family("unostat_last_sample_timestamp_seconds", "Unix time of the latest collected snapshot.")
+	p.sample("unostat_last_sample_timestamp_seconds", "", "", float64(snapshot.Timestamp.UnixNano())/1e9)
+
+	p.family("unostat_cpu_utilization_percent", "CPU utilization percentage.")
+	p.sample("unostat_cpu_utilization_percent", "", "", snapshot.CPU)
+
+	// IOWait is omitted when unavailable on the platform
+	if snapshot.CPUWait >= 0 {
+		p.family("unostat_cpu_iowait_percent", "CPU iowait percentage.")
+		p.sample("unostat_cpu_iowait_percent", "", "", snapshot.CPUWait)
+	}
+
+	p.family("unostat_cpu_time_percent", "Share of CPU time spent in each mode, as a percentage of total CPU time.")
+	for _, mode := range cpuModes(snapshot.CPUTimes) {
+		p.sample("unostat_cpu_time_percent", "mode", mode.name, mode.value)
+	}
+
+	// Per-core metrics are only present in per-CPU mode
+	cores := sortedCoreNames(snapshot.Cores)
+	if len(cores) > 0 {
+		_, maxUtil, _ := snapshot.MaxCore()
+		p.family("unostat_cpu_max_core_utilization_percent", "Utilization percentage of the busiest logical core.")
+		p.sample("unostat_cpu_max_core_utilization_percent", "", "", maxUtil)
+		p.family("unostat_cpu_core_utilization_percent", "CPU utilization percentage per logical core.")
+		for _, core := range cores {
+			p.sample("unostat_cpu_core_utilization_percent", "core", core, snapshot.Cores[core].Utilization)
+		}
+		if snapshot.CPUWait >= 0 {
+			p.family("unostat_cpu_core_iowait_percent", "CPU iowait percentage per logical core.")
+			for _, core := range cores {
+				p.sample("unostat_cpu_core_iowait_percent", "core", core, snapshot.Cores[core].IOWait)
+			}
+		}
+	}
+
+	p.family("unostat_memory_utilization_percent", "Memory utilization percentage.")
+	p.sample("unostat_memory_utilization_percent", "", "", snapshot.Memory)
+
+	mem := snapshot.MemDetail
+	p.family("unostat_memory_bytes", "Memory breakdown in bytes by type.")
+	for _, m := range []struct {
+		kind  string
+		bytes uint64
+	}{
+		{"available", mem.Available},
+		{"cached", mem.Cached},
+		{"buffers", mem.Buffers},
+		{"dirty", mem.Dirty},
+		{"writeback", mem.WriteBack},
+		{"slab", mem.Slab},
+	} {
+		p.sample("unostat_memory_bytes", "type", m.kind, float64(m.bytes))
+	}
+	p.family("unostat_swap_used_bytes", "Used swap space in bytes.")
+	p.sample("unostat_swap_used_bytes", "", "", float64(mem.SwapUsed))
+	p.family("unostat_swap_total_bytes", "Total swap space in bytes.")
+	p.sample("unostat_swap_total_bytes", "", "", float64(mem.SwapTotal))
+	p.family("unostat_swap_pages_per_second", "Pages swapped in or out per second.")
+	p.sample("unostat_swap_pages_per_second", "direction", "in", mem.SwapIn)
+	p.sample("unostat_swap_pages_per_second", "direction", "out", mem.SwapOut)
+
+	devices := sortedKeys(snapshot.Disks)
+	if len(devices) > 0 {
+		p.family("unostat_disk_utilization_percent", "Percentage of time the disk was busy.")
+		for _, device := range devices {
+			p.sample("unostat_disk_utilization_percent", "device", device, snapshot.Disks[device].Utilization)
+		}
+		p.family("unostat_disk_await_milliseconds", "Average wait time of disk I/O operations in milliseconds.")
+		for _, device := range devices {
+			p.sample("unostat_disk_await_milliseconds", "device", device, snapshot.Disks[device].Await)
+		}
+		p.family("unostat_disk_iops", "Disk I/O operations per second.")
+		for _, device := range devices {
+			p.sample("unostat_disk_iops", "device", device, snapshot.Disks[device].IOPS)
+		}
+		for _, m := range []struct {
+			name, help string
+			value      func(metrics.DiskStats) float64
+		}{
+			{"unostat_disk_reads_per_second", "Completed disk reads per second.",
+				func(d metrics.DiskStats) float64 { return d.ReadIOPS }},
+			{"unostat_disk_writes_per_second", "Completed disk writes per second.",
+				func(d metrics.DiskStats) float64 { return d.WriteIOPS }},
+			{"unostat_disk_read_bytes_per_second", "Bytes read from the disk per second.",
+				func(d metrics.DiskStats) float64 { return d.ReadThroughput }},
+			{"unostat_disk_write_bytes_per_second", "Bytes written to the disk per second.",
+				func(d metrics.DiskStats) float64 { return d.WriteThroughput }},
+			{"unostat_disk_read_await_milliseconds", "Average wait time of disk reads in milliseconds.",
+				func(d metrics.DiskStats) float64 { return d.ReadAwait }},
+			{"unostat_disk_write_await_milliseconds", "Average wait time of disk writes in milliseconds.",
+				func(d metrics.DiskStats) float64 { return d.WriteAwait }},
+			{"unostat_disk_avg_request_bytes", "Average size of disk I/O requests in bytes.",
+				func(d metrics.DiskStats) float64 { return d.AvgRequestSize }},
+			{"unostat_disk_avg_queue_size", "Average number of disk I/O requests in flight.",
+				func(d metrics.DiskStats) float64 { return d.QueueDepth }},
+		} {
+			p.family(m.name, m.help)
+			for _, device := range devices {
+				p.sample(m.name, "device", device, m.value(snapshot.Disks[device]))
+			}
+		}
+	}
+
+	ifaces := sortedKeys(snapshot.Networks)
+	if len(ifaces) > 0 {
+		p.family("unostat_network_bandwidth_bits_per_second", "Network bandwidth (sent + received) in bits per second.")
+		for _, iface := range ifaces {
+			p.sample("unostat_network_bandwidth_bits_per_second", "interface", iface, snapshot.Networks[iface].Bandwidth)
+		}
+		for _, m := range []struct {
+			name, help string
+			value      func(metrics.NetStats) float64
+		}{
+			{"unostat_network_receive_bits_per_second", "Received bits per second.",
+				func(n metrics.NetStats) float64 { return n.RxBandwidth }},
+			{"unostat_network_transmit_bits_per_second", "Transmitted bits per second.",
+				func(n metrics.NetStats) float64 { return n.TxBandwidth }},
+			{"unostat_network_receive_packets_per_second", "Received packets per second.",
+				func(n metrics.NetStats) float64 { return n.RxPackets }},
+			{"unostat_network_transmit_packets_per_second", "Transmitted packets per second.",
+				func(n metrics.NetStats) float64 { return n.TxPackets }},
+			{"unostat_network_receive_errors_per_second", "Receive errors per second.",
+				func(n metrics.NetStats) float64 { return n.ErrIn }},
+			{"unostat_network_transmit_errors_per_second", "Transmit errors per second.",
+				func(n metrics.NetStats) float64 { return n.ErrOut }},
+			{"unostat_network_receive_drops_per_second", "Dropped incoming packets per second.",
+				func(n metrics.NetStats) float64 { return n.DropIn }},
+			{"unostat_network_transmit_drops_per_second", "Dropped outgoing packets per second.",
+				func(n metrics.NetStats) float64 { return n.DropOut }},
+		} {
+			p.family(m.name, m.help)
+			for _, iface := range ifaces {
+				p.sample(m.name, "interface", iface, m.value(snapshot.Networks[iface]))
+			}
+		}
+	}
+
+	mounts := sortedKeys(snapshot.Filesystems)
+	if len(mounts) > 0 {
+		for _, m := range []struct {
+			name, help string
+			value      func(metrics.FilesystemStats) float64
+		}{
+			{"unostat_filesystem_size_bytes", "Filesystem size in bytes.",
+				func(f metrics.FilesystemStats) float64 { return float64(f.Total) }},
+			{"unostat_filesystem_used_bytes", "Used filesystem space in bytes.",
+				func(f metrics.FilesystemStats) float64 { return float64(f.Used) }},
+			{"unostat_filesystem_free_bytes", "Filesystem space available to unprivileged users in bytes.",
+				func(f metrics.FilesystemStats) float64 { return float64(f.Free) }},
+			{"unostat_filesystem_used_percent", "Used filesystem space percentage.",
+				func(f metrics.FilesystemStats) float64 { return f.UsedPercent }},
+			{"unostat_filesystem_inodes_used", "Used inodes.",
+				func(f metrics.FilesystemStats) float64 { return float64(f.InodesUsed) }},
+			{"unostat_filesystem_inodes_free", "Free inodes.",
+				func(f metrics.FilesystemStats) float64 { return float64(f.InodesFree) }},
+			{"unostat_filesystem_inodes_used_percent", "Used inodes percentage.",
+				func(f metrics.FilesystemStats) float64 { return f.InodesUsedPercent }},
+		} {
+			p.family(m.name, m.help)
+			for _, mount := range mounts {
+				p.sample(m.name, "mountpoint", mount, m.value(snapshot.Filesystems[mount]))
+			}
+		}
+	}
+
+	procs := sortedKeys(snapshot.Processes)
+	if len(procs) > 0 {
+		for _, m := range []struct {
+			name, help string
+			value      func(metrics.ProcessStats) float64
+		}{
+			{"unostat_process_count", "Number of processes matched by the selector.",
+				func(s metrics.ProcessStats) float64 { return float64(s.Count) }},
+			{"unostat_process_cpu_percent", "CPU utilization percentage of the matched processes (100 = one core).",
+				func(s metrics.ProcessStats) float64 { return s.CPU }},
+			{"unostat_process_resident_memory_bytes", "Resident set size of the matched processes in bytes.",
+				func(s metrics.ProcessStats) float64 { return float64(s.RSS) }},
+			{"unostat_process_threads", "Number of threads of the matched processes.",
+				func(s metrics.ProcessStats) float64 { return float64(s.Threads) }},
+			{"unostat_process_open_fds", "Number of open file descriptors of the matched processes.",
+				func(s metrics.ProcessStats) float64 { return float64(s.FDs) }},
+			{"unostat_process_read_bytes_per_second", "Bytes read from storage per second by the matched processes.",
+				func(s metrics.ProcessStats) float64 { return s.ReadBytes }},
+			{"unostat_process_write_bytes_per_second", "Bytes written to storage per second by the matched processes.",
+				func(s metrics.ProcessStats) float64 { return s.WriteBytes }},
+			{"unostat_process_context_switches_per_second", "Context switches per second of the matched processes.",
+				func(s metrics.ProcessStats) float64 { return s.CtxSwitches }},
+		} {
+			p.family(m.name, m.help)
+			for _, proc := range procs {
+				p.sample(m.name, "process", proc, m.value(snapshot.Processes[proc]))
+			}
+		}
+	}
+
+	cgroups := sortedKeys(snapshot.Cgroups)
+	if len(cgroups) > 0 {
+		for _, m := range []struct {
+			name, help string
+			value      func(metrics.CgroupStats) float64
+		}{
+			{"unostat_cgroup_cpu_percent", "CPU utilization percentage of the cgroup (100 = one core).",
+				func(s metrics.CgroupStats) float64 { return s.CPU }},
+			{"unostat_cgroup_cpu_user_percent", "User CPU utilization percentage of the cgroup.",
+				func(s metrics.CgroupStats) float64 { return s.CPUUser }},
+			{"unostat_cgroup_cpu_system_percent", "System CPU utilization percentage of the cgroup.",
+				func(s metrics.CgroupStats) float64 { return s.CPUSystem }},
+			{"unostat_cgroup_cpu_throttled_periods_percent", "Percentage of enforcement periods in which the cgroup was throttled.",
+				func(s metrics.CgroupStats) float64 { return s.ThrottledPercent }},
+			{"unostat_cgroup_cpu_throttled_milliseconds_per_second", "Milliseconds per second the cgroup was throttled.",
+				func(s metrics.CgroupStats) float64 { return s.ThrottledTime }},
+			{"unostat_cgroup_memory_bytes", "Memory usage of the cgroup in bytes.",
+				func(s metrics.CgroupStats) float64 { return float64(s.MemoryCurrent) }},
+			{"unostat_cgroup_pids", "Number of tasks in the cgroup.",
+				func(s metrics.CgroupStats) float64 { return float64(s.PIDs) }},
+		} {
+			p.family(m.name, m.help)
+			for _, cg := range cgroups {
+				p.sample(m.name, "cgroup", cg, m.value(snapshot.Cgroups[cg]))
+			}
+		}
+
+		// Limits are only exposed for cgroups that have one
+		p.family("unostat_cgroup_memory_max_bytes", "Memory limit of the cgroup in bytes.")
+		for _, cg := range cgroups {
+			if stats := snapshot.Cgroups[cg]; stats.MemoryMax > 0 {
+				p.sample("unostat_cgroup_memory_max_bytes", "cgroup", cg, float64(stats.MemoryMax))
+			}
+		}
+
+		for _, m := range []struct {
+			name, help string
+			value      func(metrics.CgroupIOStats) float64
+		}{
+			{"unostat_cgroup_io_read_bytes_per_second", "Bytes read per second by the cgroup.",
+				func(s metrics.CgroupIOStats) float64 { return s.ReadBytes }},
+			{"unostat_cgroup_io_write_bytes_per_second", "Bytes written per second by the cgroup.",
+				func(s metrics.CgroupIOStats) float64 { return s.WriteBytes }},
+			{"unostat_cgroup_io_read_iops", "Read operations per second of the cgroup.",
+				func(s metrics.CgroupIOStats) float64 { return s.ReadIOPS }},
+			{"unostat_cgroup_io_write_iops", "Write operations per second of the cgroup.",
+				func(s metrics.CgroupIOStats) float64 { return s.WriteIOPS }},
+		} {
+			p.family(m.name, m.help)
+			for _, cg := range cgroups {
+				devices := snapshot.Cgroups[cg].IO
+				for _, dev := range sortedKeys(devices) {
+					p.printf("%s{cgroup=\"%s\",device=\"%s\"} %s\n", m.name,
+						escapePromLabel(cg), escapePromLabel(dev), formatPromValue(m.value(devices[dev])))
+				}
+			}
+		}
+	}
+
+	resources := sortedKeys(snapshot.Pressure)
+	if len(resources) > 0 {
+		// Full stalls are only exposed for resources that report them
+		for _, m := range []struct {
+			name, help string
+			value      func(metrics.PressureStats) float64
+		}{
+			{"unostat_pressure_some_percent", "Percentage of the interval in which some tasks stalled on the resource.",
+				func(s metrics.PressureStats) float64 { return s.Some }},
+			{"unostat_pressure_full_percent", "Percentage of the interval in which all non-idle tasks stalled on the resource.",
+				func(s metrics.PressureStats) float64 { return s.Full }},
+			{"unostat_pressure_some_avg10_percent", "Kernel 10s average of the share of time some tasks stalled on the resource.",
+				func(s metrics.PressureStats) float64 { return s.SomeAvg10 }},
+			{"unostat_pressure_some_avg60_percent", "Kernel 60s average of the share of time some tasks stalled on the resource.",
+				func(s metrics.PressureStats) float64 { return s.SomeAvg60 }},
+			{"unostat_pressure_full_avg10_percent", "Kernel 10s average of the share of time all non-idle tasks stalled on the resource.",
+				func(s metrics.PressureStats) float64 { return s.FullAvg10 }},
+			{"unostat_pressure_full_avg60_percent", "Kernel 60s average of the share of time all non-idle tasks stalled on the resource.",
+				func(s metrics.PressureStats) float64 { return s.FullAvg60 }},
+		} {
+			p.family(m.name, m.help)
+			for _, resource := range resources {
+				if value := m.value(snapshot.Pressure[resource]); value >= 0 {
+					p.sample(m.name, "resource", resource, value)
+				}
+			}
+		}
+	}
+
+	if system := snapshot.System; system != nil {
+		for _, m := range []struct {
+			name, help string
+			value      float64
+		}{
+			{"unostat_system_load1", "1-minute load average.", system.Load1},
+			{"unostat_system_load5", "5-minute load average.", system.Load5},
+			{"unostat_system_load15", "15-minute load average.", system.Load15},
+			{"unostat_system_context_switches_per_second", "Context switches per second.", system.ContextSwitches},
+			{"unostat_system_interrupts_per_second", "Interrupts per second.", system.Interrupts},
+			{"unostat_system_forks_per_second", "Processes created per second.", system.Forks},
+			{"unostat_system_procs_running", "Number of runnable tasks.", float64(system.ProcsRunning)},
+			{"unostat_system_procs_blocked", "Number of tasks blocked on I/O.", float64(system.ProcsBlocked)},
+		} {
+			p.family(m.name, m.help)
+			p.sample(m.name, "", "", m.value)
+		}
+	}
+
+	if netstat := snapshot.Netstat; netstat != nil {
+		p.family("unostat_tcp_connections", "Number of TCP connections by state.")
+		for _, state := range metrics.TCPStates {
+			p.sample("unostat_tcp_connections", "state", state, float64(netstat.TCPStates[state]))
+		}
+		for _, m := range []struct {
+			name, help string
+			value      float64
+		}{
+			{"unostat_tcp_active_opens_per_second", "Outgoing TCP connections opened per second.", netstat.ActiveOpens},
+			{"unostat_tcp_passive_opens_per_second", "Incoming TCP connections accepted per second.", netstat.PassiveOpens},
+			{"unostat_tcp_retransmitted_segments_per_second", "Retransmitted TCP segments per second.", netstat.RetransSegs},
+			{"unostat_tcp_listen_overflows_per_second", "TCP connections dropped per second because an accept queue was full.", netstat.ListenOverflows},
+			{"unostat_tcp_listen_drops_per_second", "TCP connections dropped per second at listening sockets.", netstat.ListenDrops},
+			{"unostat_udp_receive_errors_per_second", "Undeliverable UDP datagrams received per second.", netstat.UDPInErrors},
+			{"unostat_udp_receive_buffer_errors_per_second", "UDP datagrams dropped per second because a receive buffer was full.", netstat.UDPRcvbufErrors},
+		} {
+			p.family(m.name, m.help)
+			p.sample(m.name, "", "", m.value)
+		}
+	}
+
+	p.family("unostat_counter_resets", "Number of sources (devices, processes, cgroups...) whose counters were reset in the latest snapshot.")
+	p.sample("unostat_counter_resets", "", "", float64(len(snapshot.CounterResets)))
+
+	return p.err
+}
+
+// cpuMode is a named CPU time component.
+type cpuMode struct {
+	name  string
+	value float64
+}
+
+// cpuModes lists the components of a CPU time breakdown in a fixed order.
+func cpuModes(b metrics.CPUBreakdown) []cpuMode {
+	return []cpuMode{
+		{"user", b.User},
+		{"system", b.System},
+		{"irq", b.Irq},
+		{"softirq", b.SoftIrq},
+		{"steal", b.Steal},
+		{"guest", b.Guest},
+	}
+}
+
+// sortedKeys returns the keys of a map in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedCoreNames returns the core names of a per-core map in numeric order.
+func sortedCoreNames[V any](cores map[string]V) []string {
+	names := sortedKeys(cores)
+	sortCoreNames(names)
+	return names
+}
+
+// sortCoreNames sorts core names by their numeric index, so cpu2 comes before cpu10.
+// Names without an index fall back to lexical order.
+func sortCoreNames(names []string) {
+	slices.SortFunc(names, func(a, b string) int {
+		ai, aErr := strconv.Atoi(strings.TrimPrefix(a, "cpu"))
+		bi, bErr := strconv.Atoi(strings.TrimPrefix(b, "cpu"))
+		if aErr == nil && bErr == nil {
+			return cmp.Compare(ai, bi)
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// formatPromValue formats a float using the shortest exact representation.
+func formatPromValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// promLabelEscaper escapes label values per the text exposition format.
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapePromLabel escapes a label value.
+func escapePromLabel(v string) string {
+	return promLabelEscaper.Replace(v)
+}
@@ -0,0 +1,154 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+func TestWritePrometheusText(t *testing.T) {
+	snapshot := &metrics.Snapshot{
+		Timestamp: time.Unix(1700000000, 0),
+		CPU:       45.5,
+		CPUWait:   2.5,
+		Memory:    60,
+		Disks: map[string]metrics.DiskStats{
+			"sdb": {Utilization: 1, Await: 2, IOPS: 3},
+			"sda": {Utilization: 10.5, Await: 5, IOPS: 100},
+		},
+		Networks: map[string]metrics.NetStats{
+			`eth"0`: {Bandwidth: 10_000_000},
+		},
+		Filesystems: map[string]metrics.FilesystemStats{
+			"/": {Used: 1024, UsedPercent: 25},
+		},
+		Processes: map[string]metrics.ProcessStats{
+			"name:nginx": {Count: 3, CPU: 150, RSS: 2048},
+		},
+		System: &metrics.SystemStats{Load1: 1.5, ContextSwitches: 2500, ProcsRunning: 4},
+	}
+
+	var sb strings.Builder
+	if err := writePrometheusText(&sb, snapshot); err != nil {
+		t.Fatalf("writePrometheusText() error = %v", err)
+	}
+	out := sb.String()
+
+	wantLines := []string{
+		"# TYPE unostat_cpu_utilization_percent gauge",
+		"unostat_last_sample_timestamp_seconds 1.7e+09",
+		"unostat_cpu_utilization_percent 45.5",
+		"unostat_cpu_iowait_percent 2.5",
+		"unostat_memory_utilization_percent 60",
+		`unostat_disk_utilization_percent{device="sda"} 10.5`,
+		`unostat_disk_await_milliseconds{device="sda"} 5`,
+		`unostat_disk_iops{device="sda"} 100`,
+		`unostat_network_bandwidth_bits_per_second{interface="eth\"0"} 1e+07`,
+		`unostat_filesystem_used_bytes{mountpoint="/"} 1024`,
+		`unostat_filesystem_used_percent{mountpoint="/"} 25`,
+		`unostat_process_count{process="name:nginx"} 3`,
+		`unostat_process_cpu_percent{process="name:nginx"} 150`,
+		`unostat_process_resident_memory_bytes{process="name:nginx"} 2048`,
+		"unostat_system_load1 1.5",
+		"unostat_system_context_switches_per_second 2500",
+		"unostat_system_procs_running 4",
+		"unostat_counter_resets 0",
+	}
+	for _, line := range wantLines {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("output missing line %q\n%s", line, out)
+		}
+	}
+
+	// Devices are sorted for stable output
+	if strings.Index(out, `device="sda"`) > strings.Index(out, `device="sdb"`) {
+		t.Error("disk samples are not sorted by device")
+	}
+}
+
+func TestWritePrometheusText_NA(t *testing.T) {
+	var sb strings.Builder
+	if err := writePrometheusText(&sb, nil); err != nil {
+		t.Fatalf("writePrometheusText(nil) error = %v", err)
+	}
+	if sb.Len() != 0 {
+		t.Errorf("expected empty output before the first snapshot, got %q", sb.String())
+	}
+
+	sb.Reset()
+	if err := writePrometheusText(&sb, &metrics.Snapshot{CPUWait: -1}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sb.String(), "iowait") {
+		t.Error("iowait should be omitted when unavailable")
+	}
+}
+
+func TestPrometheusExporter_Serve(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exp := NewPrometheusExporter(&config.Config{PrometheusListen: "127.0.0.1:0"}, logger)
+
+	if err := exp.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() {
+		if err := exp.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	}()
+
+	if err := exp.Write(&metrics.Snapshot{Timestamp: time.Now(), CPU: 12.5, CPUWait: -1}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get("http://" + exp.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "unostat_cpu_utilization_percent 12.5\n") {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+}
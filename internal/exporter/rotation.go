@@ -0,0 +1,390 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+)
+
+// Placeholders supported in the rotated file name template.
+const (
+	placeholderHost  = "{host}"
+	placeholderStart = "{start}"
+	placeholderIndex = "{index}"
+)
+
+// startTimeLayout formats the {start} placeholder, matching the default output file name.
+const startTimeLayout = "20060102150405"
+
+// rotator decides when an output file must be rotated, names the next file and
+// prunes old files according to the retention policy. It is shared by the file sinks.
+type rotator struct {
+	basePath     string         // Initial output path; rotated files live in the same directory
+	template     string         // File name template without extension (empty = `<base>_<N><ext>`)
+	hostname     string         // Value of the {host} placeholder
+	location     *time.Location // Timezone for interval alignment and {start}
+	maxSize      int64          // Rotate once the file reaches this size
+	interval     string         // "", hourly or daily
+	nextBoundary time.Time      // Next wall clock boundary for interval rotation
+	maxFiles     int            // Keep at most this many files (0 = unlimited)
+	maxAge       time.Duration  // Delete files older than this (0 = unlimited)
+	index        int            // Index of the last rotated file
+	compress     string         // Compression applied to closed files: none or gzip
+	compressWG   sync.WaitGroup // Tracks background compressions
+
+	mu       sync.Mutex      // Guards the retention state below, shared with background compressions
+	produced []string        // Files written by this rotator, oldest first; the only retention candidates
+	current  string          // File currently being written
+	pending  map[string]bool // Files being compressed, which must not be pruned yet
+
+	// companions returns files that belong to an output file and are deleted with it (optional)
+	companions func(path string) []string
+}
+
+// newRotator creates the rotation policy for a sink writing to basePath.
+func newRotator(cfg *config.Config, basePath string, loc *time.Location) *rotator {
+	maxSize := cfg.RotateSize
+	if maxSize <= 0 {
+		maxSize = config.DefaultMaxOutputFileSize
+	}
+
+	return &rotator{
+		basePath: basePath,
+		template: cfg.FileNameTemplate,
+		hostname: config.SanitizedHostname(),
+		location: loc,
+		maxSize:  maxSize,
+		interval: cfg.RotateInterval,
+		maxFiles: cfg.MaxFiles,
+		maxAge:   cfg.MaxAge,
+		compress: cfg.Compress,
+	}
+}
+
+// due reports whether the file must be rotated before writing a record taken at ts.
+// It returns a short reason for logging.
+func (r *rotator) due(size int64, ts time.Time) (bool, string) {
+	if size >= r.maxSize {
+		return true, "size"
+	}
+
+	if r.interval == "" {
+		return false, ""
+	}
+
+	// The first record only arms the wall clock boundary
+	if r.nextBoundary.IsZero() {
+		r.nextBoundary = alignedBoundary(ts, r.interval, r.location)
+		return false, ""
+	}
+
+	if !ts.Before(r.nextBoundary) {
+		r.nextBoundary = alignedBoundary(ts, r.interval, r.location)
+		return true, r.interval
+	}
+
+	return false, ""
+}
+
+// alignedBoundary returns the next hour or midnight after t in the given location.
+func alignedBoundary(t time.Time, interval string, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch interval {
+	case config.RotateHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+	case config.RotateDaily:
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+	default:
+		return time.Time{}
+	}
+}
+
+// nextPath returns the path of the next file, which must not exist yet.
+// start is the time of the first record written to it.
+func (r *rotator) nextPath(start time.Time) string {
+	ext := filepath.Ext(r.basePath)
+	base := strings.TrimSuffix(r.basePath, ext)
+	dir := filepath.Dir(r.basePath)
+
+	for {
+		r.index++
+
+		var newPath string
+		if r.template == "" {
+			newPath = fmt.Sprintf("%s_%d%s", base, r.index, ext)
+		} else {
+			name := r.expand(start, r.index)
+			if !strings.Contains(r.template, placeholderIndex) && r.index > 1 {
+				name = fmt.Sprintf("%s_%d", name, r.index)
+			}
+			newPath = filepath.Join(dir, name+ext)
+		}
+
+		// Avoid overwriting previous run data or manual files, including compressed ones
+		if !r.taken(newPath) {
+			return newPath
+		}
+	}
+}
+
+// taken reports whether path, its compressed version or one of its companions exists.
+func (r *rotator) taken(path string) bool {
+	paths := []string{path, path + ".gz"}
+	if r.companions != nil {
+		paths = append(paths, r.companions(path)...)
+	}
+	for _, p := range paths {
+		if _, err := os.Lstat(p); !os.IsNotExist(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// expand fills the template placeholders.
+func (r *rotator) expand(start time.Time, index int) string {
+	return strings.NewReplacer(
+		placeholderHost, r.hostname,
+		placeholderStart, start.In(r.location).Format(startTimeLayout),
+		placeholderIndex, strconv.Itoa(index),
+	).Replace(r.template)
+}
+
+// prune records current as written by this rotator, then deletes the oldest files it wrote
+// beyond the retention limits. Files from earlier runs or other tools are never deleted.
+// The file currently being written is never deleted and counts towards maxFiles, and files
+// still being compressed are left for the prune that follows their compression.
+func (r *rotator) prune(current string, logger *slog.Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.current = current
+	if !slices.Contains(r.produced, current) {
+		r.produced = append(r.produced, current)
+	}
+	r.pruneLocked(logger)
+}
+
+// pruneLocked applies the retention limits around r.current. The caller must hold r.mu.
+func (r *rotator) pruneLocked(logger *slog.Logger) {
+	if r.maxFiles <= 0 && r.maxAge <= 0 {
+		return
+	}
+
+	type candidate struct {
+		path    string // Actual path, with .gz once compressed
+		written string // Path as produced
+		modTime time.Time
+	}
+
+	var candidates []candidate
+	for _, written := range r.produced {
+		if written == r.current {
+			continue
+		}
+		path := written
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			path = written + ".gz"
+			info, err = os.Stat(path)
+		}
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate{path: path, written: written, modTime: info.ModTime()})
+	}
+
+	// Oldest first
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].modTime.Before(candidates[j].modTime)
+	})
+
+	now := time.Now()
+	keep := len(candidates) + 1 // +1 for the current file
+	for _, c := range candidates {
+		tooMany := r.maxFiles > 0 && keep > r.maxFiles
+		tooOld := r.maxAge > 0 && now.Sub(c.modTime) > r.maxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if r.compressing(c.written) {
+			continue
+		}
+
+		if err := os.Remove(c.path); err != nil {
+			logger.Warn("Failed to remove old output file", "path", c.path, "error", err)
+			continue
+		}
+		keep--
+		r.produced = slices.DeleteFunc(r.produced, func(p string) bool { return p == c.written })
+		logger.Info("Removed old output file", "path", c.path, "reason", retentionReason(tooMany))
+
+		if r.companions != nil {
+			for _, companion := range r.companions(c.path) {
+				if err := os.Remove(companion); err != nil && !os.IsNotExist(err) {
+					logger.Warn("Failed to remove companion file", "path", companion, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// compressing reports whether a file or one of its companions is being compressed.
+// The caller must hold r.mu.
+func (r *rotator) compressing(path string) bool {
+	if r.pending[path] {
+		return true
+	}
+	if r.companions != nil {
+		for _, companion := range r.companions(path) {
+			if r.pending[companion] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// retentionReason describes why a file was pruned.
+func retentionReason(tooMany bool) string {
+	if tooMany {
+		return "max-files"
+	}
+	return "max-age"
+}
+
+// compressClosed compresses a file that is no longer written to in the background,
+// replacing it with `<path>.gz`. It is a no-op unless compression is enabled.
+func (r *rotator) compressClosed(path string, logger *slog.Logger) {
+	if r.compress != config.CompressGzip {
+		return
+	}
+
+	r.mu.Lock()
+	if r.pending == nil {
+		r.pending = make(map[string]bool)
+	}
+	r.pending[path] = true
+	r.mu.Unlock()
+
+	r.compressWG.Add(1)
+	go func() {
+		defer r.compressWG.Done()
+		err := gzipFile(path)
+
+		if err != nil {
+			logger.Error("Failed to compress rotated file", "path", path, "error", err)
+		} else {
+			logger.Info("Compressed rotated file", "path", path+".gz")
+		}
+
+		// Apply the retention limits the file was exempt from while being compressed
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.pending, path)
+		r.pruneLocked(logger)
+	}()
+}
+
+// wait blocks until background compressions have finished.
+func (r *rotator) wait() {
+	r.compressWG.Wait()
+}
+
+// gzipFile compresses path to path.gz and removes the original. An existing path.gz is never
+// overwritten: the original is kept instead.
+// The archive is written to a temporary file first so a crash never leaves a truncated .gz.
+func gzipFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	tmpPath := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(path)
+	if _, err = io.Copy(zw, src); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	if err = dst.Sync(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	if err = placeNew(tmpPath, path+".gz"); err != nil {
+		return err
+	}
+
+	_ = src.Close()
+	return os.Remove(path)
+}
+
+// placeNew moves src to dst, failing if dst already exists.
+// A hard link refuses to replace dst atomically; filesystems without hard links fall back
+// to a rename after checking that dst does not exist.
+func placeNew(src, dst string) error {
+	err := os.Link(src, dst)
+	if err == nil {
+		return os.Remove(src)
+	}
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists", dst)
+	}
+	if _, statErr := os.Lstat(dst); !os.IsNotExist(statErr) {
+		return fmt.Errorf("%s already exists", dst)
+	}
+	return os.Rename(src, dst)
+}
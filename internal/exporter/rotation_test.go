@@ -0,0 +1,228 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/phuonguno98/unostat/internal/config"
+)
+
+func TestAlignedBoundary(t *testing.T) {
+	loc := time.FixedZone("UTC+7", 7*3600)
+	ts := time.Date(2024, 3, 10, 14, 35, 12, 0, loc)
+
+	if got, want := alignedBoundary(ts, config.RotateHourly, loc), time.Date(2024, 3, 10, 15, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("hourly boundary = %v, want %v", got, want)
+	}
+	if got, want := alignedBoundary(ts, config.RotateDaily, loc), time.Date(2024, 3, 11, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("daily boundary = %v, want %v", got, want)
+	}
+}
+
+func TestRotator_Due(t *testing.T) {
+	cfg := &config.Config{RotateSize: 100, RotateInterval: config.RotateHourly}
+	r := newRotator(cfg, "/tmp/out.csv", time.UTC)
+	base := time.Date(2024, 1, 1, 10, 59, 0, 0, time.UTC)
+
+	if due, _ := r.due(0, base); due {
+		t.Error("first record must only arm the boundary")
+	}
+	if due, _ := r.due(0, base.Add(30*time.Second)); due {
+		t.Error("rotation before the boundary")
+	}
+	if due, reason := r.due(0, base.Add(time.Minute)); !due || reason != config.RotateHourly {
+		t.Errorf("due at boundary = %v (%s), want true (hourly)", due, reason)
+	}
+	if due, _ := r.due(0, base.Add(2*time.Minute)); due {
+		t.Error("boundary must advance after rotating")
+	}
+	if due, reason := r.due(100, base.Add(3*time.Minute)); !due || reason != "size" {
+		t.Errorf("due at size limit = %v (%s), want true (size)", due, reason)
+	}
+}
+
+func TestRotator_NextPathTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{FileNameTemplate: "{host}_{start}_{index}"}
+	r := newRotator(cfg, filepath.Join(tempDir, "out.csv"), time.UTC)
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	host := config.SanitizedHostname()
+
+	want := filepath.Join(tempDir, host+"_20240102030405_1.csv")
+	if got := r.nextPath(start); got != want {
+		t.Errorf("nextPath() = %q, want %q", got, want)
+	}
+
+	// Existing files are skipped
+	if err := os.WriteFile(filepath.Join(tempDir, host+"_20240102030405_2.csv"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want = filepath.Join(tempDir, host+"_20240102030405_3.csv")
+	if got := r.nextPath(start); got != want {
+		t.Errorf("nextPath() = %q, want %q", got, want)
+	}
+}
+
+func TestRotator_NextPathSkipsCompressedAndCompanions(t *testing.T) {
+	tempDir := t.TempDir()
+	r := newRotator(&config.Config{}, filepath.Join(tempDir, "out.csv"), time.UTC)
+	r.companions = topSidecarCompanions
+
+	// Files of an earlier run, compressed or only represented by their sidecar
+	for _, name := range []string{"out_1.csv.gz", "out_2.top.jsonl", "out_3.top.jsonl.gz"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := filepath.Join(tempDir, "out_4.csv")
+	if got := r.nextPath(time.Now()); got != want {
+		t.Errorf("nextPath() = %q, want %q", got, want)
+	}
+}
+
+func TestGzipFile_NoOverwrite(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "out_1.csv")
+	if err := os.WriteFile(path, []byte("new run"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".gz", []byte("previous run"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gzipFile(path); err == nil {
+		t.Error("gzipFile() should refuse to overwrite an existing archive")
+	}
+	if data, err := os.ReadFile(path + ".gz"); err != nil || string(data) != "previous run" {
+		t.Errorf("existing archive = %q, %v; want it untouched", data, err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("original must be kept when it cannot be compressed: %v", err)
+	}
+	if _, err := os.Stat(path + ".gz.tmp"); !os.IsNotExist(err) {
+		t.Error("temporary archive must be removed")
+	}
+}
+
+func TestRotator_Prune(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// createFiles creates out.csv, out_1.csv ... out_4.csv with increasing ages, as written by r,
+	// plus files of an earlier run and an unrelated file, which are older still.
+	createFiles := func(t *testing.T, dir string, r *rotator) {
+		t.Helper()
+		names := []string{"out_7.csv", "out_2023.csv", "out.csv", "out_1.csv", "out_2.csv", "out_3.csv", "out_4.csv", "other.csv"}
+		for _, name := range names[2:6] {
+			r.produced = append(r.produced, filepath.Join(dir, name))
+		}
+		now := time.Now()
+		for i, name := range names {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			mtime := now.Add(-time.Duration(len(names)-i) * time.Hour)
+			if err := os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	exists := func(dir, name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+
+	t.Run("MaxFiles", func(t *testing.T) {
+		dir := t.TempDir()
+		r := newRotator(&config.Config{MaxFiles: 3}, filepath.Join(dir, "out.csv"), time.UTC)
+		createFiles(t, dir, r)
+		r.prune(filepath.Join(dir, "out_4.csv"), logger)
+
+		for name, want := range map[string]bool{
+			"out_7.csv": true, "out_2023.csv": true, "out.csv": false, "out_1.csv": false, "out_2.csv": true,
+			"out_3.csv": true, "out_4.csv": true, "other.csv": true,
+		} {
+			if got := exists(dir, name); got != want {
+				t.Errorf("%s exists = %v, want %v", name, got, want)
+			}
+		}
+	})
+
+	t.Run("MaxAge", func(t *testing.T) {
+		dir := t.TempDir()
+		r := newRotator(&config.Config{MaxAge: 210 * time.Minute}, filepath.Join(dir, "out.csv"), time.UTC)
+		createFiles(t, dir, r)
+		r.prune(filepath.Join(dir, "out_4.csv"), logger)
+
+		for name, want := range map[string]bool{
+			"out_7.csv": true, "out_2023.csv": true, "out.csv": false, "out_1.csv": false, "out_2.csv": false,
+			"out_3.csv": true, "out_4.csv": true, "other.csv": true,
+		} {
+			if got := exists(dir, name); got != want {
+				t.Errorf("%s exists = %v, want %v", name, got, want)
+			}
+		}
+	})
+	t.Run("UnrelatedFilesSurvive", func(t *testing.T) {
+		dir := t.TempDir()
+		userFile := filepath.Join(dir, "data_2023.csv")
+		if err := os.WriteFile(userFile, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		old := time.Now().Add(-24 * time.Hour)
+		if err := os.Chtimes(userFile, old, old); err != nil {
+			t.Fatal(err)
+		}
+
+		base := filepath.Join(dir, "data.csv")
+		r := newRotator(&config.Config{MaxFiles: 1, MaxAge: time.Hour}, base, time.UTC)
+		current := base
+		for range 3 {
+			if err := os.WriteFile(current, []byte("x"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			r.prune(current, logger)
+			current = r.nextPath(time.Now())
+		}
+
+		if !exists(dir, "data_2023.csv") {
+			t.Error("data_2023.csv was pruned, but it was not written by the rotator")
+		}
+		if exists(dir, "data.csv") || exists(dir, "data_1.csv") {
+			t.Error("rotated files beyond max-files should be pruned")
+		}
+		if !exists(dir, "data_2.csv") {
+			t.Error("current file data_2.csv must be kept")
+		}
+	})
+}
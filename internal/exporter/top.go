@@ -0,0 +1,138 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/phuonguno98/unostat/pkg/metrics"
+)
+
+// topSidecarExt is the extension of the top processes sidecar of a CSV file.
+const topSidecarExt = ".top.jsonl"
+
+// topRecord is one line of the top processes sidecar.
+type topRecord struct {
+	Timestamp string       `json:"timestamp"`
+	ByCPU     []topProcess `json:"by_cpu"` // Value: CPU utilization percentage
+	ByRSS     []topProcess `json:"by_rss"` // Value: resident set size in bytes
+}
+
+// topProcess is a ranked process in the sidecar.
+type topProcess struct {
+	PID   int32   `json:"pid"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// topSidecarPath returns the sidecar path of a CSV output file, e.g. host_1.csv -> host_1.top.jsonl.
+func topSidecarPath(csvPath string) string {
+	return strings.TrimSuffix(csvPath, filepath.Ext(csvPath)) + topSidecarExt
+}
+
+// topSidecarCompanions returns the sidecar files belonging to a (possibly compressed) CSV file,
+// so they are deleted together with it.
+func topSidecarCompanions(csvPath string) []string {
+	sidecar := topSidecarPath(strings.TrimSuffix(csvPath, ".gz"))
+	return []string{sidecar, sidecar + ".gz"}
+}
+
+// topSidecar writes the top processes of every snapshot next to the CSV file they belong to.
+// The file is opened on the first ranking, so nothing is created unless the top collector runs.
+type topSidecar struct {
+	file      *os.File
+	bufWriter *bufio.Writer
+	location  *time.Location
+}
+
+// write appends the ranking of a snapshot to the sidecar of csvPath.
+func (t *topSidecar) write(csvPath string, snapshot *metrics.Snapshot) error {
+	if t.file == nil {
+		file, err := os.OpenFile(topSidecarPath(csvPath), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open top processes file: %w", err)
+		}
+		t.file = file
+		t.bufWriter = bufio.NewWriterSize(file, 8192)
+	}
+
+	line, err := json.Marshal(topRecord{
+		Timestamp: snapshot.Timestamp.In(t.location).Format(time.RFC3339),
+		ByCPU:     toTopProcesses(snapshot.TopProcesses.ByCPU),
+		ByRSS:     toTopProcesses(snapshot.TopProcesses.ByRSS),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode top processes: %w", err)
+	}
+	_, err = t.bufWriter.Write(append(line, '\n'))
+	return err
+}
+
+// toTopProcesses converts a ranking, never returning nil so it encodes as an empty list.
+func toTopProcesses(procs []metrics.TopProcess) []topProcess {
+	out := make([]topProcess, 0, len(procs))
+	for _, p := range procs {
+		out = append(out, topProcess(p))
+	}
+	return out
+}
+
+// flush writes buffered rankings to the file.
+func (t *topSidecar) flush() error {
+	if t.file == nil {
+		return nil
+	}
+	return t.bufWriter.Flush()
+}
+
+// sync commits the written rankings to stable storage.
+func (t *topSidecar) sync() error {
+	if t.file == nil {
+		return nil
+	}
+	return t.file.Sync()
+}
+
+// close flushes and closes the sidecar. It returns the path of the closed file,
+// or an empty string if no sidecar was open.
+func (t *topSidecar) close() (string, error) {
+	if t.file == nil {
+		return "", nil
+	}
+	path := t.file.Name()
+	err := t.bufWriter.Flush()
+	if closeErr := t.file.Close(); err == nil {
+		err = closeErr
+	}
+	t.file = nil
+	t.bufWriter = nil
+	return path, err
+}
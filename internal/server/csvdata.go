@@ -25,7 +25,12 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -71,10 +76,31 @@ type ColumnData struct {
 	Values     map[string][]float64 // Map column name to slice of values (aligned with Timestamps)
 }
 
+// TopProcesses is the ranking of the heaviest processes recorded at a timestamp.
+type TopProcesses struct {
+	Timestamp time.Time    `json:"timestamp"`
+	ByCPU     []TopProcess `json:"by_cpu"` // Value: CPU utilization percentage
+	ByRSS     []TopProcess `json:"by_rss"` // Value: resident set size in bytes
+}
+
+// TopProcess is a ranked process.
+type TopProcess struct {
+	PID   int32   `json:"pid"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// ErrNoTopProcesses is returned when a file has no top processes sidecar.
+var ErrNoTopProcesses = errors.New("no top processes recorded for this file")
+
+// topSidecarExt is the extension of the top processes sidecar written next to a CSV file.
+const topSidecarExt = ".top.jsonl"
+
 // CSVDataService manages CSV files and provides data access.
 type CSVDataService struct {
 	files      map[string]*CSVFile
 	columnData map[string]*ColumnData
+	topData    map[string][]TopProcesses // Sidecar rankings by file ID, sorted by time
 	mu         sync.RWMutex
 	logger     *slog.Logger
 	timezone   *time.Location // Timezone for parsing timestamps in CSV files
@@ -100,6 +126,7 @@ func NewCSVDataService(logger *slog.Logger, timezone string) *CSVDataService {
 	return &CSVDataService{
 		files:      make(map[string]*CSVFile),
 		columnData: make(map[string]*ColumnData),
+		topData:    make(map[string][]TopProcesses),
 		logger:     logger,
 		timezone:   loc,
 	}
@@ -143,6 +170,7 @@ func (s *CSVDataService) LoadFile(id, name, path string) error {
 
 	s.files[id] = fileMeta
 	s.columnData[id] = parsedCols
+	delete(s.topData, id) // The sidecar may have grown with the file; read it again on next use
 
 	return nil
 }
@@ -204,10 +232,55 @@ func (s *CSVDataService) LoadFileContent(id string) error {
 
 	s.files[id] = newMeta
 	s.columnData[id] = parsedCols
+	delete(s.topData, id) // The sidecar may have grown with the file; read it again on next use
 
 	return nil
 }
 
+// gzipMagic is the header of a gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// errDecompressedTooLarge is returned when a compressed file expands beyond MaxFileSize.
+var errDecompressedTooLarge = fmt.Errorf("decompressed file too large (max %d MB)", MaxFileSize/(1024*1024))
+
+// openCSVContent returns a reader over the CSV text in r, transparently decompressing gzip input.
+// Compression is detected from the content rather than the file name, so renamed files still load.
+func openCSVContent(r io.Reader) (io.Reader, func() error, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, err
+	}
+	if !bytes.Equal(magic, gzipMagic) {
+		return br, func() error { return nil }, nil
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid gzip stream: %w", err)
+	}
+	return &maxSizeReader{r: zr, remaining: MaxFileSize}, zr.Close, nil
+}
+
+// maxSizeReader fails once more than remaining bytes have been read,
+// bounding memory use for compressed input.
+type maxSizeReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	if m.remaining <= 0 {
+		return 0, errDecompressedTooLarge
+	}
+	if int64(len(p)) > m.remaining {
+		p = p[:m.remaining]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	return n, err
+}
+
 // processCSVFile reads and parses the CSV file into columnar format.
 func (s *CSVDataService) processCSVFile(id, name, path string) (*ColumnData, *CSVFile, error) {
 	// Check file size
@@ -229,7 +302,17 @@ func (s *CSVDataService) processCSVFile(id, name, path string) (*ColumnData, *CS
 		}
 	}()
 
-	reader := csv.NewReader(file)
+	content, closeContent, err := openCSVContent(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		if err := closeContent(); err != nil {
+			s.logger.Error("failed to close decompressor", "path", path, "error", err)
+		}
+	}()
+
+	reader := csv.NewReader(content)
 	reader.ReuseRecord = true
 
 	// Read header
@@ -262,6 +345,14 @@ func (s *CSVDataService) processCSVFile(id, name, path string) (*ColumnData, *CS
 			break
 		}
 		if err != nil {
+			// A malformed final row is what an interrupted write leaves behind; keep the rows before it
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				if _, nextErr := reader.Read(); nextErr == io.EOF {
+					s.logger.Warn("Skipping malformed trailing row", "path", path, "line", parseErr.Line, "error", err)
+					break
+				}
+			}
 			return nil, nil, fmt.Errorf("error reading CSV line %d: %w", rowCount+2, err)
 		}
 
@@ -494,6 +585,7 @@ func (s *CSVDataService) DeleteFile(id string) error {
 
 	delete(s.files, id)
 	delete(s.columnData, id)
+	delete(s.topData, id)
 
 	return nil
 }
@@ -506,6 +598,85 @@ func (s *CSVDataService) DeleteAll() {
 	// Clear maps
 	s.files = make(map[string]*CSVFile)
 	s.columnData = make(map[string]*ColumnData)
+	s.topData = make(map[string][]TopProcesses)
+}
+
+// GetTopProcesses returns the ranking recorded at or most recently before ts.
+// The sidecar is read on first use. A ranking with empty lists is returned if nothing
+// was recorded before ts; ErrNoTopProcesses if the file has no sidecar.
+func (s *CSVDataService) GetTopProcesses(fileID string, ts time.Time) (*TopProcesses, error) {
+	s.mu.RLock()
+	file, ok := s.files[fileID]
+	entries, cached := s.topData[fileID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("file not found: %s", fileID)
+	}
+
+	if !cached {
+		var err error
+		entries, err = s.loadTopSidecar(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.topData[fileID] = entries
+		s.mu.Unlock()
+	}
+
+	// Last entry at or before ts
+	idx := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Timestamp.After(ts)
+	})
+	if idx == 0 {
+		return &TopProcesses{ByCPU: []TopProcess{}, ByRSS: []TopProcess{}}, nil
+	}
+	return &entries[idx-1], nil
+}
+
+// loadTopSidecar reads the top processes sidecar of a CSV file, which may be gzip compressed.
+// Lines that cannot be parsed (e.g. a torn last line) are skipped.
+func (s *CSVDataService) loadTopSidecar(csvPath string) ([]TopProcesses, error) {
+	base := csvPath[:len(csvPath)-len(csvExt(csvPath))]
+
+	var f *os.File
+	for _, path := range []string{base + topSidecarExt, base + topSidecarExt + ".gz"} {
+		var err error
+		if f, err = os.Open(path); err == nil {
+			break
+		}
+	}
+	if f == nil {
+		return nil, ErrNoTopProcesses
+	}
+	defer func() { _ = f.Close() }()
+
+	content, closeContent, err := openCSVContent(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read top processes: %w", err)
+	}
+	defer func() { _ = closeContent() }()
+
+	var entries []TopProcesses
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry TopProcesses
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			s.logger.Warn("Skipping malformed top processes line", "path", f.Name(), "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read top processes: %w", err)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
 }
 
 // parseTimestamp parses a timestamp string using the specified location.
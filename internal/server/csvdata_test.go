@@ -25,6 +25,8 @@
 package server
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"log/slog"
@@ -328,3 +330,109 @@ func TestCSVDataService_Downsampling(t *testing.T) {
 		t.Errorf("First value too high: %f", data[0].Value)
 	}
 }
+
+func TestCSVDataService_GzipFile(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := NewCSVDataService(logger, "UTC")
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte("Timestamp,CPU\n2024-01-01 00:00:00,10\n2024-01-01 00:00:30,20\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Detection is content based, so both the .csv.gz name and a misleading .csv name load
+	for _, name := range []string{"metrics.csv.gz", "renamed.csv"} {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := service.LoadFile(name, name, path); err != nil {
+			t.Fatalf("LoadFile(%s) error = %v", name, err)
+		}
+		file, _ := service.GetFile(name)
+		if file.RowCount != 2 {
+			t.Errorf("%s: RowCount = %d, want 2", name, file.RowCount)
+		}
+	}
+
+	// A corrupt gzip stream is reported instead of being parsed as text
+	corruptPath := filepath.Join(tempDir, "corrupt.csv.gz")
+	if err := os.WriteFile(corruptPath, buf.Bytes()[:len(buf.Bytes())/2], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.LoadFile("corrupt", "Corrupt", corruptPath); err == nil {
+		t.Error("LoadFile(corrupt) expected error")
+	}
+}
+
+func TestCSVDataService_TornTrailingRow(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := NewCSVDataService(logger, "UTC")
+
+	// A crash mid-write leaves a short last row: the rows before it still load
+	tornPath := filepath.Join(tempDir, "torn.csv")
+	if err := os.WriteFile(tornPath, []byte("Timestamp,CPU,Memory\n2024-01-01 00:00:00,10,20\n2024-01-01 00:00:30,1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.LoadFile("torn", "Torn", tornPath); err != nil {
+		t.Fatalf("LoadFile(torn) error = %v", err)
+	}
+	if file, _ := service.GetFile("torn"); file.RowCount != 1 {
+		t.Errorf("RowCount = %d, want 1", file.RowCount)
+	}
+
+	// The same damage in the middle of the file is still an error
+	middlePath := filepath.Join(tempDir, "middle.csv")
+	if err := os.WriteFile(middlePath, []byte("Timestamp,CPU,Memory\n2024-01-01 00:00:00,1\n2024-01-01 00:00:30,10,20\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.LoadFile("middle", "Middle", middlePath); err == nil {
+		t.Error("LoadFile(middle) expected error")
+	}
+}
+
+func TestCSVDataService_ReloadRereadsTopSidecar(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := NewCSVDataService(logger, "UTC")
+
+	csvPath := filepath.Join(tempDir, "live.csv")
+	topPath := filepath.Join(tempDir, "live.top.jsonl")
+	if err := os.WriteFile(csvPath, []byte("Timestamp,CPU\n2024-01-02 03:04:05,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	first := `{"timestamp":"2024-01-02T03:04:05Z","by_cpu":[{"pid":42,"name":"java","value":180.5}],"by_rss":[]}` + "\n"
+	if err := os.WriteFile(topPath, []byte(first), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.LoadFile("live", "Live", csvPath); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	later := time.Date(2024, 1, 2, 3, 4, 10, 0, time.UTC)
+	if top, err := service.GetTopProcesses("live", later); err != nil || top.ByCPU[0].Name != "java" {
+		t.Fatalf("GetTopProcesses() = %v, %v", top, err)
+	}
+
+	// The collector keeps writing both files
+	second := `{"timestamp":"2024-01-02T03:04:10Z","by_cpu":[{"pid":7,"name":"gzip","value":99}],"by_rss":[]}` + "\n"
+	if err := os.WriteFile(topPath, []byte(first+second), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.LoadFile("live", "Live", csvPath); err != nil {
+		t.Fatalf("LoadFile() reload error = %v", err)
+	}
+	top, err := service.GetTopProcesses("live", later)
+	if err != nil {
+		t.Fatalf("GetTopProcesses() error = %v", err)
+	}
+	if top.ByCPU[0].Name != "gzip" {
+		t.Errorf("top CPU process after reload = %q, want gzip (stale sidecar cache)", top.ByCPU[0].Name)
+	}
+}
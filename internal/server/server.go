@@ -89,13 +89,14 @@ func (s *Server) scanExistingFiles() {
 
 	count := 0
 	for _, file := range files {
-		if file.IsDir() || !strings.HasSuffix(strings.ToLower(file.Name()), ".csv") {
+		ext := csvExt(file.Name())
+		if file.IsDir() || ext == "" {
 			continue
 		}
 
 		fileName := file.Name()
 		// ID is the filename without extension (e.g., MyFile_20230101)
-		id := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		id := fileName[:len(fileName)-len(ext)]
 		path := filepath.Join(s.uploadDir, fileName)
 
 		// Try to extract display name by removing the suffix (timestamp/uuid)
@@ -119,6 +120,18 @@ func (s *Server) scanExistingFiles() {
 	}
 }
 
+// csvExt returns the CSV extension of name (".csv" or ".csv.gz", matched case-insensitively),
+// or an empty string if name is not a supported CSV file.
+func csvExt(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".csv.gz", ".csv"} {
+		if strings.HasSuffix(lower, ext) {
+			return name[len(name)-len(ext):]
+		}
+	}
+	return ""
+}
+
 // sanitizeFilename removes unsafe characters and ensures ASCII compatible name
 func sanitizeFilename(name string) string {
 	// Sanitize path components first (security)
@@ -168,6 +181,7 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("/api/files/{id}", s.handleDeleteFile).Methods("DELETE")
 	s.router.HandleFunc("/api/files/{id}/load", s.handleLoadFile).Methods("POST")
 	s.router.HandleFunc("/api/files/{id}/metrics", s.handleGetMetrics).Methods("GET")
+	s.router.HandleFunc("/api/files/{id}/top", s.handleGetTopProcesses).Methods("GET")
 	s.router.HandleFunc("/api/data/{fileId}/{metric}", s.handleGetData).Methods("GET")
 
 	// Static files from embedded FS
@@ -294,7 +308,8 @@ func (s *Server) handleUploadFile(w http.ResponseWriter, r *http.Request) {
 	}()
 
 	// Validate file extension
-	if filepath.Ext(header.Filename) != ".csv" {
+	ext := csvExt(header.Filename)
+	if ext != ".csv" && ext != ".csv.gz" {
 		s.writeError(w, "Only CSV files are allowed", http.StatusBadRequest)
 		return
 	}
@@ -307,17 +322,18 @@ func (s *Server) handleUploadFile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 1. Sanitize the original filename to be safe for disk/URL
-	safeName := sanitizeFilename(filename)
+	// Strip ".gz" first so sanitizeFilename sees the same name as for a plain CSV
+	safeName := sanitizeFilename(strings.TrimSuffix(filename, ".gz"))
 
 	// 2. Add UUID suffix to ensure uniqueness and prevent overwrites
-	// Format: Name_UUID.csv
+	// Format: Name_UUID.csv (or Name_UUID.csv.gz, kept compressed on disk)
 	// Using a separator '_' to splitting later
 	// Note: We used to use timestamp, but collisions are possible in high concurrency
 	id := uuid.New().String()
 	fileID := fmt.Sprintf("%s_%s", safeName, id)
 
 	// 3. Construct paths
-	fileNameOnDisk := fileID + ".csv"
+	fileNameOnDisk := fileID + ext
 	filePath := filepath.Join(s.uploadDir, fileNameOnDisk)
 
 	dst, err := os.Create(filePath)
@@ -367,12 +383,14 @@ func (s *Server) handleDeleteFile(w http.ResponseWriter, r *http.Request) {
 		s.logger.Warn("File not found in memory during delete", "id", fileID)
 	}
 
-	// Remove from disk
-	filePath := filepath.Join(s.uploadDir, fileID+".csv")
-	if err := os.Remove(filePath); err != nil {
-		if !os.IsNotExist(err) {
-			s.logger.Error("Failed to delete file from disk", "path", filePath, "error", err)
-			// We don't return error to client if memory delete was successful or if file is already gone
+	// Remove from disk (either the plain or the compressed copy)
+	for _, ext := range []string{".csv", ".csv.gz"} {
+		filePath := filepath.Join(s.uploadDir, fileID+ext)
+		if err := os.Remove(filePath); err != nil {
+			if !os.IsNotExist(err) {
+				s.logger.Error("Failed to delete file from disk", "path", filePath, "error", err)
+				// We don't return error to client if memory delete was successful or if file is already gone
+			}
 		}
 	}
 
@@ -387,7 +405,7 @@ func (s *Server) handleDeleteAllFiles(w http.ResponseWriter, _ *http.Request) {
 	s.dataService.DeleteAll()
 
 	// 2. Clear disk (uploads directory)
-	// We read the directory and remove all .csv files to be safe, rather than deleting the folder itself
+	// We read the directory and remove all .csv and .csv.gz files to be safe, rather than deleting the folder itself
 	// to preserve the directory permissions/structure.
 	entries, err := os.ReadDir(s.uploadDir)
 	if err != nil {
@@ -398,7 +416,7 @@ func (s *Server) handleDeleteAllFiles(w http.ResponseWriter, _ *http.Request) {
 
 	deletedCount := 0
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".csv" {
+		if !entry.IsDir() && csvExt(entry.Name()) != "" {
 			path := filepath.Join(s.uploadDir, entry.Name())
 			if err := os.Remove(path); err != nil {
 				s.logger.Error("Failed to delete file", "path", path, "error", err)
@@ -448,6 +466,26 @@ func (s *Server) handleGetMetrics(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleGetTopProcesses returns the heaviest processes recorded at or just before
+// the RFC3339 'timestamp' query parameter.
+func (s *Server) handleGetTopProcesses(w http.ResponseWriter, r *http.Request) {
+	fileID := mux.Vars(r)["id"]
+
+	ts, err := time.Parse(time.RFC3339, r.URL.Query().Get("timestamp"))
+	if err != nil {
+		s.writeError(w, "Invalid or missing timestamp (RFC3339 expected)", http.StatusBadRequest)
+		return
+	}
+
+	top, err := s.dataService.GetTopProcesses(fileID, ts)
+	if err != nil {
+		s.writeError(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	s.writeJSON(w, top)
+}
+
 // handleGetData returns time series data for a specific metric in a file.
 // Supports optional 'from' and 'to' query parameters for time range filtering.
 func (s *Server) handleGetData(w http.ResponseWriter, r *http.Request) {
@@ -26,6 +26,7 @@ package server
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"io"
 	"log/slog"
@@ -454,6 +455,50 @@ func TestServer_LoadExistingFiles(t *testing.T) {
 	}
 }
 
+func TestServer_LoadExistingGzipFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte("Timestamp,Val\n2023-01-01 00:00:00,1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "Host_20230101.csv.gz"), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv, err := NewServer(tempDir, "Local", logger)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	file, ok := srv.dataService.GetFile("Host_20230101")
+	if !ok {
+		t.Fatal("Compressed file not registered under its ID without .csv.gz")
+	}
+	if file.Name != "Host" {
+		t.Errorf("Display name = %q, want 'Host'", file.Name)
+	}
+	if err := srv.dataService.LoadFileContent("Host_20230101"); err != nil {
+		t.Fatalf("LoadFileContent() error = %v", err)
+	}
+
+	// Deleting by ID removes the compressed file from disk
+	req := httptest.NewRequest(http.MethodDelete, "/api/files/Host_20230101", nil)
+	rr := httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Delete status = %d", rr.Code)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "Host_20230101.csv.gz")); !os.IsNotExist(err) {
+		t.Error("Compressed file should be deleted from disk")
+	}
+}
+
 func TestServer_CORS(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "unostat_cors_test")
 	if err != nil {
@@ -646,3 +691,73 @@ func TestServer_UploadDir(t *testing.T) {
 		t.Errorf("UploadDir() = %q, want %q", srv.UploadDir(), tempDir)
 	}
 }
+
+func TestServer_TopProcesses(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "host_20240102.csv"), []byte("Timestamp,Val\n2024-01-02 03:04:05,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sidecar := `{"timestamp":"2024-01-02T03:04:05Z","by_cpu":[{"pid":42,"name":"java","value":180.5}],"by_rss":[]}` + "\n" +
+		`{"timestamp":"2024-01-02T03:04:10Z","by_cpu":[{"pid":7,"name":"gzip","value":99}],"by_rss":[]}` + "\n" +
+		`{"timestamp":"2024-01-02T03:04` // Torn last line
+	if err := os.WriteFile(filepath.Join(tempDir, "host_20240102.top.jsonl"), []byte(sidecar), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "other_1.csv"), []byte("Timestamp,Val\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(tempDir, "UTC", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(id, ts string) (int, TopProcesses) {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/files/"+id+"/top?timestamp="+ts, http.NoBody)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		var top TopProcesses
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&top); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+		}
+		return w.Code, top
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		ts       string
+		wantCode int
+		wantName string
+	}{
+		{"Exact Match", "host_20240102", "2024-01-02T03:04:05Z", http.StatusOK, "java"},
+		{"Between Samples", "host_20240102", "2024-01-02T03:04:09Z", http.StatusOK, "java"},
+		{"Other Timezone", "host_20240102", "2024-01-02T10:04:10%2B07:00", http.StatusOK, "gzip"},
+		{"Before First Sample", "host_20240102", "2024-01-02T03:00:00Z", http.StatusOK, ""},
+		{"No Sidecar", "other_1", "2024-01-02T03:04:05Z", http.StatusNotFound, ""},
+		{"Unknown File", "missing", "2024-01-02T03:04:05Z", http.StatusNotFound, ""},
+		{"Invalid Timestamp", "host_20240102", "yesterday", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, top := get(tt.id, tt.ts)
+			if code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", code, tt.wantCode)
+			}
+			if code != http.StatusOK {
+				return
+			}
+			var gotName string
+			if len(top.ByCPU) > 0 {
+				gotName = top.ByCPU[0].Name
+			}
+			if gotName != tt.wantName {
+				t.Errorf("top CPU process = %q, want %q", gotName, tt.wantName)
+			}
+		})
+	}
+}
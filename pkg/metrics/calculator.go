@@ -24,6 +24,8 @@
 
 package metrics
 
+import "math"
+
 // CalculateCPUUtilization calculates CPU utilization percentage from two CPU time snapshots.
 // Formula: 100 * (1 - ΔIdle / ΔTotal)
 func CalculateCPUUtilization(prev, current *CPUTimeStats) float64 {
@@ -31,17 +33,21 @@ func CalculateCPUUtilization(prev, current *CPUTimeStats) float64 {
 		return 0.0
 	}
 
-	prevTotal := prev.User + prev.System + prev.Idle + prev.IOWait + prev.Irq + prev.SoftIrq + prev.Steal
-	currentTotal := current.User + current.System + current.Idle + current.IOWait + current.Irq + current.SoftIrq + current.Steal
-
-	deltaTotal := currentTotal - prevTotal
+	deltaTotal := cpuTotal(current) - cpuTotal(prev)
 	deltaIdle := current.Idle - prev.Idle
 
 	if deltaTotal <= 0 {
 		return 0.0
 	}
 
-	return 100.0 * (1.0 - deltaIdle/deltaTotal)
+	utilization := 100.0 * (1.0 - deltaIdle/deltaTotal)
+
+	// Clamp floating point noise (e.g. -2.8e-11 on a fully idle CPU)
+	if utilization < 0 {
+		utilization = 0
+	}
+
+	return utilization
 }
 
 // CalculateCPUIOWait calculates CPU iowait percentage from two CPU time snapshots.
@@ -57,10 +63,7 @@ func CalculateCPUIOWait(prev, current *CPUTimeStats) float64 {
 		return -1.0
 	}
 
-	prevTotal := prev.User + prev.System + prev.Idle + prev.IOWait + prev.Irq + prev.SoftIrq + prev.Steal
-	currentTotal := current.User + current.System + current.Idle + current.IOWait + current.Irq + current.SoftIrq + current.Steal
-
-	deltaTotal := currentTotal - prevTotal
+	deltaTotal := cpuTotal(current) - cpuTotal(prev)
 	deltaIOWait := current.IOWait - prev.IOWait
 
 	if deltaTotal <= 0 {
@@ -70,6 +73,40 @@ func CalculateCPUIOWait(prev, current *CPUTimeStats) float64 {
 	return 100.0 * (deltaIOWait / deltaTotal)
 }
 
+// CalculateCPUBreakdown calculates the share of each CPU time component from two CPU time snapshots.
+// Formula: 100 * (ΔComponent / ΔTotal), with the same ΔTotal as CalculateCPUUtilization.
+// Guest time is already counted in User by the kernel, so it is informational and not additive.
+func CalculateCPUBreakdown(prev, current *CPUTimeStats) CPUBreakdown {
+	if prev.Timestamp.IsZero() {
+		return CPUBreakdown{}
+	}
+
+	deltaTotal := cpuTotal(current) - cpuTotal(prev)
+	if deltaTotal <= 0 {
+		return CPUBreakdown{}
+	}
+
+	share := func(prevValue, currentValue float64) float64 {
+		// Clamp floating point noise and counters going backwards
+		return max(100.0*(currentValue-prevValue)/deltaTotal, 0)
+	}
+
+	return CPUBreakdown{
+		User:    share(prev.User, current.User),
+		System:  share(prev.System, current.System),
+		Irq:     share(prev.Irq, current.Irq),
+		SoftIrq: share(prev.SoftIrq, current.SoftIrq),
+		Steal:   share(prev.Steal, current.Steal),
+		Guest:   share(prev.Guest+prev.GuestNice, current.Guest+current.GuestNice),
+	}
+}
+
+// cpuTotal returns the total CPU time used as the denominator of the CPU percentages.
+// Guest time is excluded because it is already included in User.
+func cpuTotal(s *CPUTimeStats) float64 {
+	return s.User + s.System + s.Idle + max(s.IOWait, 0) + s.Irq + s.SoftIrq + s.Steal
+}
+
 // CalculateDiskUtilization calculates disk utilization percentage from two I/O snapshots.
 // Formula: (ΔIOTime / Δt) × 100
 func CalculateDiskUtilization(prev, current DiskIOStats) float64 {
@@ -82,7 +119,7 @@ func CalculateDiskUtilization(prev, current DiskIOStats) float64 {
 		return 0.0
 	}
 
-	deltaIOTime := float64(current.IOTime - prev.IOTime)
+	deltaIOTime := float64(counterDelta(prev.IOTime, current.IOTime, current.Counter32))
 	utilization := (deltaIOTime / float64(deltaTime)) * 100.0
 
 	// Cap at 100% (can exceed due to rounding or multiple queues)
@@ -100,16 +137,16 @@ func CalculateDiskAwait(prev, current DiskIOStats) float64 {
 		return 0.0
 	}
 
-	deltaReadCount := current.ReadCount - prev.ReadCount
-	deltaWriteCount := current.WriteCount - prev.WriteCount
+	deltaReadCount := counterDelta(prev.ReadCount, current.ReadCount, current.Counter32)
+	deltaWriteCount := counterDelta(prev.WriteCount, current.WriteCount, current.Counter32)
 	totalOps := deltaReadCount + deltaWriteCount
 
 	if totalOps == 0 {
 		return 0.0
 	}
 
-	deltaReadTime := current.ReadTime - prev.ReadTime
-	deltaWriteTime := current.WriteTime - prev.WriteTime
+	deltaReadTime := counterDelta(prev.ReadTime, current.ReadTime, current.Counter32)
+	deltaWriteTime := counterDelta(prev.WriteTime, current.WriteTime, current.Counter32)
 	totalTime := deltaReadTime + deltaWriteTime
 
 	return float64(totalTime) / float64(totalOps)
@@ -127,13 +164,255 @@ func CalculateDiskIOPS(prev, current DiskIOStats) float64 {
 		return 0.0
 	}
 
-	deltaReadCount := current.ReadCount - prev.ReadCount
-	deltaWriteCount := current.WriteCount - prev.WriteCount
+	deltaReadCount := counterDelta(prev.ReadCount, current.ReadCount, current.Counter32)
+	deltaWriteCount := counterDelta(prev.WriteCount, current.WriteCount, current.Counter32)
 	totalOps := deltaReadCount + deltaWriteCount
 
 	return float64(totalOps) / deltaTime
 }
 
+// CalculateDiskReadWriteIOPS calculates read and write operations per second (r/s, w/s).
+// Formula: ΔReadCount / Δt, ΔWriteCount / Δt
+func CalculateDiskReadWriteIOPS(prev, current DiskIOStats) (reads, writes float64) {
+	if prev.Timestamp.IsZero() {
+		return 0.0, 0.0
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Seconds()
+	if deltaTime <= 0 {
+		return 0.0, 0.0
+	}
+
+	return float64(counterDelta(prev.ReadCount, current.ReadCount, current.Counter32)) / deltaTime,
+		float64(counterDelta(prev.WriteCount, current.WriteCount, current.Counter32)) / deltaTime
+}
+
+// CalculateDiskReadWriteThroughput calculates read and write throughput in bytes per second.
+// Formula: ΔReadBytes / Δt, ΔWriteBytes / Δt
+func CalculateDiskReadWriteThroughput(prev, current DiskIOStats) (reads, writes float64) {
+	if prev.Timestamp.IsZero() {
+		return 0.0, 0.0
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Seconds()
+	if deltaTime <= 0 {
+		return 0.0, 0.0
+	}
+
+	return float64(counterDelta(prev.ReadBytes, current.ReadBytes, current.Counter32)) / deltaTime,
+		float64(counterDelta(prev.WriteBytes, current.WriteBytes, current.Counter32)) / deltaTime
+}
+
+// CalculateDiskReadWriteAwait calculates the average wait time of reads and writes in milliseconds (r_await, w_await).
+// Formula: ΔReadTime / ΔReadCount, ΔWriteTime / ΔWriteCount
+func CalculateDiskReadWriteAwait(prev, current DiskIOStats) (reads, writes float64) {
+	if prev.Timestamp.IsZero() {
+		return 0.0, 0.0
+	}
+
+	if deltaReads := counterDelta(prev.ReadCount, current.ReadCount, current.Counter32); deltaReads > 0 {
+		reads = float64(counterDelta(prev.ReadTime, current.ReadTime, current.Counter32)) / float64(deltaReads)
+	}
+	if deltaWrites := counterDelta(prev.WriteCount, current.WriteCount, current.Counter32); deltaWrites > 0 {
+		writes = float64(counterDelta(prev.WriteTime, current.WriteTime, current.Counter32)) / float64(deltaWrites)
+	}
+
+	return reads, writes
+}
+
+// CalculateDiskAvgRequestSize calculates the average size of I/O requests in bytes.
+// Formula: Δ(ReadBytes + WriteBytes) / Δ(ReadCount + WriteCount)
+func CalculateDiskAvgRequestSize(prev, current DiskIOStats) float64 {
+	if prev.Timestamp.IsZero() {
+		return 0.0
+	}
+
+	totalOps := (counterDelta(prev.ReadCount, current.ReadCount, current.Counter32)) + (counterDelta(prev.WriteCount, current.WriteCount, current.Counter32))
+	if totalOps == 0 {
+		return 0.0
+	}
+
+	totalBytes := (counterDelta(prev.ReadBytes, current.ReadBytes, current.Counter32)) + (counterDelta(prev.WriteBytes, current.WriteBytes, current.Counter32))
+
+	return float64(totalBytes) / float64(totalOps)
+}
+
+// CalculateDiskQueueDepth calculates the average number of requests in flight (aqu-sz).
+// Formula: ΔWeightedIO / Δt, both in milliseconds
+func CalculateDiskQueueDepth(prev, current DiskIOStats) float64 {
+	if prev.Timestamp.IsZero() {
+		return 0.0
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Milliseconds()
+	if deltaTime <= 0 {
+		return 0.0
+	}
+
+	return float64(counterDelta(prev.WeightedIO, current.WeightedIO, current.Counter32)) / float64(deltaTime)
+}
+
+// CalculateSwapRates calculates the swap-in and swap-out rates in pages per second.
+// Formula: ΔPagesIn / Δt, ΔPagesOut / Δt
+func CalculateSwapRates(prev, current SwapIOStats) (in, out float64) {
+	if prev.Timestamp.IsZero() {
+		return 0.0, 0.0
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Seconds()
+	if deltaTime <= 0 {
+		return 0.0, 0.0
+	}
+
+	deltaIn := counterDelta(prev.PagesIn, current.PagesIn, false)
+	deltaOut := counterDelta(prev.PagesOut, current.PagesOut, false)
+
+	return float64(deltaIn) / deltaTime, float64(deltaOut) / deltaTime
+}
+
+// CalculateProcessRates calculates a process's CPU utilization and I/O and context switch rates.
+// CPU is a percentage of one core, so a process using two cores fully reports 200%.
+// Formula: ΔCPUTime / Δt × 100, ΔReadBytes / Δt, ΔWriteBytes / Δt, ΔCtxSwitches / Δt
+func CalculateProcessRates(prev, current ProcessIOStats) (cpu, readBytes, writeBytes, ctxSwitches float64) {
+	if prev.Timestamp.IsZero() {
+		return 0.0, 0.0, 0.0, 0.0
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Seconds()
+	if deltaTime <= 0 {
+		return 0.0, 0.0, 0.0, 0.0
+	}
+
+	if deltaCPU := current.CPUTime - prev.CPUTime; deltaCPU > 0 {
+		cpu = deltaCPU / deltaTime * 100.0
+	}
+
+	return cpu,
+		float64(counterDelta(prev.ReadBytes, current.ReadBytes, false)) / deltaTime,
+		float64(counterDelta(prev.WriteBytes, current.WriteBytes, false)) / deltaTime,
+		float64(counterDelta(prev.CtxSwitches, current.CtxSwitches, false)) / deltaTime
+}
+
+// CalculateCgroupCPU calculates the CPU utilization and throttling of a cgroup.
+// CPU percentages are relative to one core.
+// Formula: ΔUsageUsec / (Δt × 10^6) × 100, ΔNrThrottled / ΔNrPeriods × 100, ΔThrottledUsec / 1000 / Δt
+func CalculateCgroupCPU(prev, current CgroupCPUCounters) (usage, user, system, throttledPercent, throttledTime float64) {
+	if prev.Timestamp.IsZero() {
+		return 0.0, 0.0, 0.0, 0.0, 0.0
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Seconds()
+	if deltaTime <= 0 {
+		return 0.0, 0.0, 0.0, 0.0, 0.0
+	}
+
+	percent := func(prevUsec, currentUsec uint64) float64 {
+		return float64(counterDelta(prevUsec, currentUsec, false)) / (deltaTime * 1e6) * 100.0
+	}
+	usage = percent(prev.UsageUsec, current.UsageUsec)
+	user = percent(prev.UserUsec, current.UserUsec)
+	system = percent(prev.SystemUsec, current.SystemUsec)
+
+	if deltaPeriods := counterDelta(prev.NrPeriods, current.NrPeriods, false); deltaPeriods > 0 {
+		throttledPercent = float64(counterDelta(prev.NrThrottled, current.NrThrottled, false)) / float64(deltaPeriods) * 100.0
+	}
+	throttledTime = float64(counterDelta(prev.ThrottledUsec, current.ThrottledUsec, false)) / 1000.0 / deltaTime
+
+	return usage, user, system, throttledPercent, throttledTime
+}
+
+// CalculateCgroupIO calculates the I/O rates of a cgroup on one device.
+// Formula: ΔBytes / Δt, ΔIOs / Δt
+func CalculateCgroupIO(prev, current CgroupIOCounters) CgroupIOStats {
+	if prev.Timestamp.IsZero() {
+		return CgroupIOStats{}
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Seconds()
+	if deltaTime <= 0 {
+		return CgroupIOStats{}
+	}
+
+	return CgroupIOStats{
+		ReadBytes:  float64(counterDelta(prev.ReadBytes, current.ReadBytes, false)) / deltaTime,
+		WriteBytes: float64(counterDelta(prev.WriteBytes, current.WriteBytes, false)) / deltaTime,
+		ReadIOPS:   float64(counterDelta(prev.ReadIOs, current.ReadIOs, false)) / deltaTime,
+		WriteIOPS:  float64(counterDelta(prev.WriteIOs, current.WriteIOs, false)) / deltaTime,
+	}
+}
+
+// CalculatePressureStall calculates the share of the interval in which tasks stalled on a resource.
+// Formula: ΔTotalUsec / (Δt × 10^6) × 100
+// full is -1.0 if the kernel does not report full stalls for the resource.
+func CalculatePressureStall(prev, current PressureCounters) (some, full float64) {
+	full = -1.0
+	if prev.Timestamp.IsZero() {
+		return 0.0, full
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Seconds()
+	if deltaTime <= 0 {
+		return 0.0, full
+	}
+
+	// Stall time can't exceed the interval; clamp sampling jitter
+	stall := func(prevUsec, currentUsec uint64) float64 {
+		return math.Min(float64(counterDelta(prevUsec, currentUsec, false))/(deltaTime*1e6)*100.0, 100.0)
+	}
+	some = stall(prev.SomeTotal, current.SomeTotal)
+	if prev.HasFull && current.HasFull {
+		full = stall(prev.FullTotal, current.FullTotal)
+	}
+
+	return some, full
+}
+
+// CalculateSystemRates calculates the context switch, interrupt and fork rates per second.
+// Formula: ΔCounter / Δt
+func CalculateSystemRates(prev, current SystemCounters) (ctxSwitches, interrupts, forks float64) {
+	if prev.Timestamp.IsZero() {
+		return 0.0, 0.0, 0.0
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Seconds()
+	if deltaTime <= 0 {
+		return 0.0, 0.0, 0.0
+	}
+
+	ctxSwitches = float64(counterDelta(prev.ContextSwitches, current.ContextSwitches, false)) / deltaTime
+	interrupts = float64(counterDelta(prev.Interrupts, current.Interrupts, false)) / deltaTime
+	forks = float64(counterDelta(prev.Forks, current.Forks, false)) / deltaTime
+
+	return ctxSwitches, interrupts, forks
+}
+
+// CalculateNetstatRates calculates the per-second rates of the protocol counters.
+// Formula: ΔCounter / Δt
+// Connection counts by state are not rates and are left unset.
+func CalculateNetstatRates(prev, current NetstatCounters) NetstatStats {
+	if prev.Timestamp.IsZero() {
+		return NetstatStats{}
+	}
+
+	deltaTime := current.Timestamp.Sub(prev.Timestamp).Seconds()
+	if deltaTime <= 0 {
+		return NetstatStats{}
+	}
+
+	rate := func(prevCount, currentCount uint64) float64 {
+		return float64(counterDelta(prevCount, currentCount, false)) / deltaTime
+	}
+	return NetstatStats{
+		ActiveOpens:     rate(prev.ActiveOpens, current.ActiveOpens),
+		PassiveOpens:    rate(prev.PassiveOpens, current.PassiveOpens),
+		RetransSegs:     rate(prev.RetransSegs, current.RetransSegs),
+		ListenOverflows: rate(prev.ListenOverflows, current.ListenOverflows),
+		ListenDrops:     rate(prev.ListenDrops, current.ListenDrops),
+		UDPInErrors:     rate(prev.UDPInErrors, current.UDPInErrors),
+		UDPRcvbufErrors: rate(prev.UDPRcvbufErrors, current.UDPRcvbufErrors),
+	}
+}
+
 // CalculateNetworkBandwidth calculates network bandwidth in bits per second.
 // Formula: [Δ(BytesSent + BytesRecv) × 8] / Δt
 func CalculateNetworkBandwidth(prev, current NetworkIOStats) float64 {
@@ -146,10 +425,206 @@ func CalculateNetworkBandwidth(prev, current NetworkIOStats) float64 {
 		return 0.0
 	}
 
-	deltaSent := current.BytesSent - prev.BytesSent
-	deltaRecv := current.BytesRecv - prev.BytesRecv
+	deltaSent := counterDelta(prev.BytesSent, current.BytesSent, current.Counter32)
+	deltaRecv := counterDelta(prev.BytesRecv, current.BytesRecv, current.Counter32)
 	totalBytes := deltaSent + deltaRecv
 
 	// Convert bytes to bits and divide by time
 	return float64(totalBytes*8) / deltaTime
 }
+
+// CalculateNetworkRxTxBandwidth calculates received and transmitted bandwidth in bits per second.
+// Formula: (ΔBytesRecv × 8) / Δt, (ΔBytesSent × 8) / Δt
+func CalculateNetworkRxTxBandwidth(prev, current NetworkIOStats) (rx, tx float64) {
+	deltaTime := networkDeltaSeconds(prev, current)
+	if deltaTime <= 0 {
+		return 0.0, 0.0
+	}
+
+	return float64((counterDelta(prev.BytesRecv, current.BytesRecv, current.Counter32))*8) / deltaTime,
+		float64((counterDelta(prev.BytesSent, current.BytesSent, current.Counter32))*8) / deltaTime
+}
+
+// CalculateNetworkPacketRates calculates received and transmitted packets per second.
+// Formula: ΔPacketsRecv / Δt, ΔPacketsSent / Δt
+func CalculateNetworkPacketRates(prev, current NetworkIOStats) (rx, tx float64) {
+	deltaTime := networkDeltaSeconds(prev, current)
+	if deltaTime <= 0 {
+		return 0.0, 0.0
+	}
+
+	return float64(counterDelta(prev.PacketsRecv, current.PacketsRecv, current.Counter32)) / deltaTime,
+		float64(counterDelta(prev.PacketsSent, current.PacketsSent, current.Counter32)) / deltaTime
+}
+
+// CalculateNetworkErrorRates calculates receive and transmit errors per second.
+// Formula: ΔErrIn / Δt, ΔErrOut / Δt
+func CalculateNetworkErrorRates(prev, current NetworkIOStats) (in, out float64) {
+	deltaTime := networkDeltaSeconds(prev, current)
+	if deltaTime <= 0 {
+		return 0.0, 0.0
+	}
+
+	return float64(counterDelta(prev.ErrIn, current.ErrIn, current.Counter32)) / deltaTime,
+		float64(counterDelta(prev.ErrOut, current.ErrOut, current.Counter32)) / deltaTime
+}
+
+// CalculateNetworkDropRates calculates dropped incoming and outgoing packets per second.
+// Formula: ΔDropIn / Δt, ΔDropOut / Δt
+func CalculateNetworkDropRates(prev, current NetworkIOStats) (in, out float64) {
+	deltaTime := networkDeltaSeconds(prev, current)
+	if deltaTime <= 0 {
+		return 0.0, 0.0
+	}
+
+	return float64(counterDelta(prev.DropIn, current.DropIn, current.Counter32)) / deltaTime,
+		float64(counterDelta(prev.DropOut, current.DropOut, current.Counter32)) / deltaTime
+}
+
+// networkDeltaSeconds returns the seconds elapsed between two network snapshots,
+// or 0 if there is no previous snapshot.
+func networkDeltaSeconds(prev, current NetworkIOStats) float64 {
+	if prev.Timestamp.IsZero() {
+		return 0.0
+	}
+	return current.Timestamp.Sub(prev.Timestamp).Seconds()
+}
+
+// counterDelta returns the increase of a monotonic counter between two samples.
+// A decrease is a counter reset (interface re-created, driver reload, reboot) and yields 0
+// rather than an enormous bogus value; when wrap32 is set, a decrease of two values that
+// fit in 32 bits is instead a single wraparound of a 32-bit counter.
+func counterDelta(prev, current uint64, wrap32 bool) uint64 {
+	delta, ok := CounterDelta(prev, current, wrap32)
+	if !ok {
+		return 0
+	}
+	return delta
+}
+
+// CounterDelta returns the increase of a monotonic counter between two samples.
+// ok is false if the counter was reset, in which case the delta is meaningless.
+// When wrap32 is set, a decrease of two values that fit in 32 bits is treated as
+// a single wraparound of a 32-bit counter rather than a reset.
+func CounterDelta(prev, current uint64, wrap32 bool) (delta uint64, ok bool) {
+	if current >= prev {
+		return current - prev, true
+	}
+	if wrap32 && prev <= math.MaxUint32 && current <= math.MaxUint32 {
+		return (math.MaxUint32 - prev) + current + 1, true
+	}
+	return 0, false
+}
+
+// DiskCountersReset reports whether any disk counter decreased between two snapshots
+// in a way that cannot be explained by a 32-bit wraparound, i.e. the device was reset
+// and the previous snapshot must not be used as a baseline.
+func DiskCountersReset(prev, current DiskIOStats) bool {
+	return countersReset(current.Counter32,
+		[2]uint64{prev.ReadCount, current.ReadCount},
+		[2]uint64{prev.WriteCount, current.WriteCount},
+		[2]uint64{prev.ReadBytes, current.ReadBytes},
+		[2]uint64{prev.WriteBytes, current.WriteBytes},
+		[2]uint64{prev.ReadTime, current.ReadTime},
+		[2]uint64{prev.WriteTime, current.WriteTime},
+		[2]uint64{prev.IOTime, current.IOTime},
+		[2]uint64{prev.WeightedIO, current.WeightedIO},
+	)
+}
+
+// NetworkCountersReset reports whether any network counter decreased between two snapshots
+// in a way that cannot be explained by a 32-bit wraparound, e.g. because the interface was re-created.
+func NetworkCountersReset(prev, current NetworkIOStats) bool {
+	return countersReset(current.Counter32,
+		[2]uint64{prev.BytesSent, current.BytesSent},
+		[2]uint64{prev.BytesRecv, current.BytesRecv},
+		[2]uint64{prev.PacketsSent, current.PacketsSent},
+		[2]uint64{prev.PacketsRecv, current.PacketsRecv},
+		[2]uint64{prev.ErrIn, current.ErrIn},
+		[2]uint64{prev.ErrOut, current.ErrOut},
+		[2]uint64{prev.DropIn, current.DropIn},
+		[2]uint64{prev.DropOut, current.DropOut},
+	)
+}
+
+// SwapCountersReset reports whether the swap activity counters decreased between two snapshots.
+func SwapCountersReset(prev, current SwapIOStats) bool {
+	return countersReset(false,
+		[2]uint64{prev.PagesIn, current.PagesIn},
+		[2]uint64{prev.PagesOut, current.PagesOut},
+	)
+}
+
+// ProcessCountersReset reports whether any counter of a process decreased between two snapshots.
+func ProcessCountersReset(prev, current ProcessIOStats) bool {
+	return current.CPUTime < prev.CPUTime || countersReset(false,
+		[2]uint64{prev.ReadBytes, current.ReadBytes},
+		[2]uint64{prev.WriteBytes, current.WriteBytes},
+		[2]uint64{prev.CtxSwitches, current.CtxSwitches},
+	)
+}
+
+// CgroupCPUCountersReset reports whether any cpu.stat counter of a cgroup decreased between two
+// snapshots, e.g. because the cgroup was removed and re-created under the same path.
+func CgroupCPUCountersReset(prev, current CgroupCPUCounters) bool {
+	return countersReset(false,
+		[2]uint64{prev.UsageUsec, current.UsageUsec},
+		[2]uint64{prev.UserUsec, current.UserUsec},
+		[2]uint64{prev.SystemUsec, current.SystemUsec},
+		[2]uint64{prev.NrPeriods, current.NrPeriods},
+		[2]uint64{prev.NrThrottled, current.NrThrottled},
+		[2]uint64{prev.ThrottledUsec, current.ThrottledUsec},
+	)
+}
+
+// CgroupIOCountersReset reports whether any io.stat counter of a cgroup decreased between two snapshots.
+func CgroupIOCountersReset(prev, current CgroupIOCounters) bool {
+	return countersReset(false,
+		[2]uint64{prev.ReadBytes, current.ReadBytes},
+		[2]uint64{prev.WriteBytes, current.WriteBytes},
+		[2]uint64{prev.ReadIOs, current.ReadIOs},
+		[2]uint64{prev.WriteIOs, current.WriteIOs},
+	)
+}
+
+// SystemCountersReset reports whether any kernel activity counter decreased between two snapshots.
+func SystemCountersReset(prev, current SystemCounters) bool {
+	return countersReset(false,
+		[2]uint64{prev.ContextSwitches, current.ContextSwitches},
+		[2]uint64{prev.Interrupts, current.Interrupts},
+		[2]uint64{prev.Forks, current.Forks},
+	)
+}
+
+// NetstatCountersReset reports whether any TCP/UDP counter decreased between two snapshots,
+// e.g. because the monitored network namespace changed.
+func NetstatCountersReset(prev, current NetstatCounters) bool {
+	return countersReset(false,
+		[2]uint64{prev.ActiveOpens, current.ActiveOpens},
+		[2]uint64{prev.PassiveOpens, current.PassiveOpens},
+		[2]uint64{prev.RetransSegs, current.RetransSegs},
+		[2]uint64{prev.ListenOverflows, current.ListenOverflows},
+		[2]uint64{prev.ListenDrops, current.ListenDrops},
+		[2]uint64{prev.UDPInErrors, current.UDPInErrors},
+		[2]uint64{prev.UDPRcvbufErrors, current.UDPRcvbufErrors},
+	)
+}
+
+// PressureCountersReset reports whether a PSI stall total decreased between two snapshots.
+// The full total is only compared when both snapshots report it.
+func PressureCountersReset(prev, current PressureCounters) bool {
+	if prev.HasFull && current.HasFull && current.FullTotal < prev.FullTotal {
+		return true
+	}
+	return countersReset(false, [2]uint64{prev.SomeTotal, current.SomeTotal})
+}
+
+// countersReset reports whether any of the previous/current counter pairs was reset.
+func countersReset(wrap32 bool, pairs ...[2]uint64) bool {
+	for _, pair := range pairs {
+		if _, ok := CounterDelta(pair[0], pair[1], wrap32); !ok {
+			return true
+		}
+	}
+	return false
+}
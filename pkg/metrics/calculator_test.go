@@ -26,6 +26,7 @@ package metrics
 
 import (
 	"math"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -132,6 +133,82 @@ func TestCalculateCPUIOWait(t *testing.T) {
 	}
 }
 
+func TestCalculateCPUBreakdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		prev     CPUTimeStats
+		current  CPUTimeStats
+		expected CPUBreakdown
+	}{
+		{
+			name: "All components",
+			prev: CPUTimeStats{
+				User: 100, System: 50, Idle: 800, IOWait: 10, Irq: 5, SoftIrq: 5, Steal: 0, Guest: 20, GuestNice: 0,
+				Timestamp: time.Now(),
+			},
+			current: CPUTimeStats{
+				User: 140, System: 70, Idle: 910, IOWait: 10, Irq: 10, SoftIrq: 15, Steal: 15, Guest: 30, GuestNice: 5,
+				Timestamp: time.Now().Add(1 * time.Second),
+			},
+			// Delta Total = 40 (U) + 20 (S) + 110 (I) + 0 (IO) + 5 (Irq) + 10 (SoftIrq) + 15 (Steal) = 200
+			// Steal: 15 of 200 = 7.5%, Guest: (10 + 5) of 200 = 7.5% (included in User, not in Total)
+			expected: CPUBreakdown{User: 20, System: 10, Irq: 2.5, SoftIrq: 5, Steal: 7.5, Guest: 7.5},
+		},
+		{
+			name: "Steal only (noisy neighbour)",
+			prev: CPUTimeStats{Idle: 100, Timestamp: time.Now()},
+			current: CPUTimeStats{
+				Idle: 150, Steal: 50,
+				Timestamp: time.Now().Add(1 * time.Second),
+			},
+			expected: CPUBreakdown{Steal: 50},
+		},
+		{
+			name: "Unavailable IOWait (Negative)",
+			prev: CPUTimeStats{User: 10, Idle: 10, IOWait: -1, Timestamp: time.Now()},
+			current: CPUTimeStats{
+				User: 20, Idle: 20, IOWait: -1,
+				Timestamp: time.Now().Add(1 * time.Second),
+			},
+			expected: CPUBreakdown{User: 50},
+		},
+		{
+			name:     "First run",
+			prev:     CPUTimeStats{},
+			current:  CPUTimeStats{User: 100, Timestamp: time.Now()},
+			expected: CPUBreakdown{},
+		},
+		{
+			name:     "No time elapsed",
+			prev:     CPUTimeStats{User: 100, Timestamp: time.Now()},
+			current:  CPUTimeStats{User: 100, Timestamp: time.Now()},
+			expected: CPUBreakdown{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateCPUBreakdown(&tt.prev, &tt.current)
+			fields := []struct {
+				name      string
+				got, want float64
+			}{
+				{"User", got.User, tt.expected.User},
+				{"System", got.System, tt.expected.System},
+				{"Irq", got.Irq, tt.expected.Irq},
+				{"SoftIrq", got.SoftIrq, tt.expected.SoftIrq},
+				{"Steal", got.Steal, tt.expected.Steal},
+				{"Guest", got.Guest, tt.expected.Guest},
+			}
+			for _, f := range fields {
+				if math.Abs(f.got-f.want) > 0.00001 {
+					t.Errorf("CalculateCPUBreakdown().%s = %v, want %v", f.name, f.got, f.want)
+				}
+			}
+		})
+	}
+}
+
 func TestCalculateDiskUtilization(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
@@ -269,6 +346,56 @@ func TestCalculateNetworkBandwidth(t *testing.T) {
 	}
 }
 
+func TestCalculateNetworkSplit(t *testing.T) {
+	now := time.Now()
+	prev := NetworkIOStats{
+		BytesSent: 1000, BytesRecv: 5000,
+		PacketsSent: 10, PacketsRecv: 50,
+		ErrIn: 1, ErrOut: 0, DropIn: 4, DropOut: 2,
+		Timestamp: now,
+	}
+	current := NetworkIOStats{
+		BytesSent: 3000, BytesRecv: 13000, // Delta 2000 tx, 8000 rx over 2s
+		PacketsSent: 30, PacketsRecv: 150, // Delta 20 tx, 100 rx
+		ErrIn: 5, ErrOut: 2, DropIn: 10, DropOut: 2, // Delta 4, 2, 6, 0
+		Timestamp: now.Add(2 * time.Second),
+	}
+
+	tests := []struct {
+		name            string
+		calculate       func(prev, current NetworkIOStats) (float64, float64)
+		wantIn, wantOut float64
+	}{
+		{"RxTxBandwidth", CalculateNetworkRxTxBandwidth, 32000, 8000},
+		{"PacketRates", CalculateNetworkPacketRates, 50, 10},
+		{"ErrorRates", CalculateNetworkErrorRates, 2, 1},
+		{"DropRates", CalculateNetworkDropRates, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, out := tt.calculate(prev, current)
+			if math.Abs(in-tt.wantIn) > 0.00001 || math.Abs(out-tt.wantOut) > 0.00001 {
+				t.Errorf("got (%v, %v), want (%v, %v)", in, out, tt.wantIn, tt.wantOut)
+			}
+
+			// First run and zero time delta
+			if in, out := tt.calculate(NetworkIOStats{}, current); in != 0 || out != 0 {
+				t.Errorf("first run = (%v, %v), want (0, 0)", in, out)
+			}
+			if in, out := tt.calculate(current, current); in != 0 || out != 0 {
+				t.Errorf("zero time delta = (%v, %v), want (0, 0)", in, out)
+			}
+		})
+	}
+
+	// The combined bandwidth stays the sum of both directions
+	rx, tx := CalculateNetworkRxTxBandwidth(prev, current)
+	if combined := CalculateNetworkBandwidth(prev, current); math.Abs(combined-(rx+tx)) > 0.00001 {
+		t.Errorf("CalculateNetworkBandwidth() = %v, want rx + tx = %v", combined, rx+tx)
+	}
+}
+
 func TestCalculateDiskIOPS(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
@@ -319,6 +446,365 @@ func TestCalculateDiskIOPS(t *testing.T) {
 	}
 }
 
+func TestCalculateDiskReadWriteSplit(t *testing.T) {
+	now := time.Now()
+	prev := DiskIOStats{
+		ReadCount: 1000, WriteCount: 2000,
+		ReadBytes: 4 << 20, WriteBytes: 8 << 20,
+		ReadTime: 500, WriteTime: 3000,
+		WeightedIO: 10000,
+		Timestamp:  now,
+	}
+	current := DiskIOStats{
+		ReadCount: 1100, WriteCount: 2300, // Delta 100 reads, 300 writes over 2s
+		ReadBytes: 4<<20 + 409600, WriteBytes: 8<<20 + 3276800, // Delta 400 KiB read, 3200 KiB written
+		ReadTime: 700, WriteTime: 4500, // Delta 200ms reading, 1500ms writing
+		WeightedIO: 13000, // Delta 3000ms weighted over 2000ms
+		Timestamp:  now.Add(2 * time.Second),
+	}
+
+	near := func(got, want float64) bool { return math.Abs(got-want) < 0.00001 }
+
+	if r, w := CalculateDiskReadWriteIOPS(prev, current); !near(r, 50) || !near(w, 150) {
+		t.Errorf("CalculateDiskReadWriteIOPS() = (%v, %v), want (50, 150)", r, w)
+	}
+	if r, w := CalculateDiskReadWriteThroughput(prev, current); !near(r, 204800) || !near(w, 1638400) {
+		t.Errorf("CalculateDiskReadWriteThroughput() = (%v, %v), want (204800, 1638400)", r, w)
+	}
+	if r, w := CalculateDiskReadWriteAwait(prev, current); !near(r, 2) || !near(w, 5) {
+		t.Errorf("CalculateDiskReadWriteAwait() = (%v, %v), want (2, 5)", r, w)
+	}
+	// (409600 + 3276800) bytes / 400 requests
+	if got := CalculateDiskAvgRequestSize(prev, current); !near(got, 9216) {
+		t.Errorf("CalculateDiskAvgRequestSize() = %v, want 9216", got)
+	}
+	if got := CalculateDiskQueueDepth(prev, current); !near(got, 1.5) {
+		t.Errorf("CalculateDiskQueueDepth() = %v, want 1.5", got)
+	}
+
+	// Idle device and first run
+	if r, w := CalculateDiskReadWriteAwait(current, current); r != 0 || w != 0 {
+		t.Errorf("CalculateDiskReadWriteAwait(idle) = (%v, %v), want (0, 0)", r, w)
+	}
+	if got := CalculateDiskAvgRequestSize(current, current); got != 0 {
+		t.Errorf("CalculateDiskAvgRequestSize(idle) = %v, want 0", got)
+	}
+	if r, w := CalculateDiskReadWriteIOPS(DiskIOStats{}, current); r != 0 || w != 0 {
+		t.Errorf("CalculateDiskReadWriteIOPS(first run) = (%v, %v), want (0, 0)", r, w)
+	}
+	if got := CalculateDiskQueueDepth(DiskIOStats{}, current); got != 0 {
+		t.Errorf("CalculateDiskQueueDepth(first run) = %v, want 0", got)
+	}
+}
+
+func TestCalculateSwapRates(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		prev        SwapIOStats
+		current     SwapIOStats
+		expectedIn  float64
+		expectedOut float64
+	}{
+		{
+			name:        "Normal swapping",
+			prev:        SwapIOStats{PagesIn: 1000, PagesOut: 500, Timestamp: now},
+			current:     SwapIOStats{PagesIn: 1200, PagesOut: 1500, Timestamp: now.Add(10 * time.Second)},
+			expectedIn:  20.0,  // 200 pages / 10s
+			expectedOut: 100.0, // 1000 pages / 10s
+		},
+		{
+			name:    "First run",
+			prev:    SwapIOStats{},
+			current: SwapIOStats{PagesIn: 1000, Timestamp: now},
+		},
+		{
+			name:    "Zero time delta",
+			prev:    SwapIOStats{PagesIn: 1000, Timestamp: now},
+			current: SwapIOStats{PagesIn: 2000, Timestamp: now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, out := CalculateSwapRates(tt.prev, tt.current)
+			if math.Abs(in-tt.expectedIn) > 0.00001 || math.Abs(out-tt.expectedOut) > 0.00001 {
+				t.Errorf("CalculateSwapRates() = (%v, %v), want (%v, %v)", in, out, tt.expectedIn, tt.expectedOut)
+			}
+		})
+	}
+}
+
+func TestCalculateProcessRates(t *testing.T) {
+	now := time.Now()
+	prev := ProcessIOStats{CPUTime: 10.0, ReadBytes: 1000, WriteBytes: 5000, CtxSwitches: 100, Timestamp: now}
+
+	tests := []struct {
+		name                                 string
+		prev                                 ProcessIOStats
+		current                              ProcessIOStats
+		wantCPU, wantRead, wantWrite, wantCS float64
+	}{
+		{
+			name:    "Two Busy Cores",
+			prev:    prev,
+			current: ProcessIOStats{CPUTime: 14.0, ReadBytes: 3000, WriteBytes: 5000, CtxSwitches: 300, Timestamp: now.Add(2 * time.Second)},
+			wantCPU: 200.0, wantRead: 1000.0, wantWrite: 0.0, wantCS: 100.0,
+		},
+		{
+			name:    "Counters Decrease",
+			prev:    prev,
+			current: ProcessIOStats{CPUTime: 1.0, ReadBytes: 10, WriteBytes: 10, CtxSwitches: 1, Timestamp: now.Add(time.Second)},
+		},
+		{
+			name:    "No Baseline",
+			current: ProcessIOStats{CPUTime: 14.0, ReadBytes: 3000, Timestamp: now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu, read, write, cs := CalculateProcessRates(tt.prev, tt.current)
+			if math.Abs(cpu-tt.wantCPU) > 0.00001 || math.Abs(read-tt.wantRead) > 0.00001 ||
+				math.Abs(write-tt.wantWrite) > 0.00001 || math.Abs(cs-tt.wantCS) > 0.00001 {
+				t.Errorf("CalculateProcessRates() = (%v, %v, %v, %v), want (%v, %v, %v, %v)",
+					cpu, read, write, cs, tt.wantCPU, tt.wantRead, tt.wantWrite, tt.wantCS)
+			}
+		})
+	}
+}
+
+func TestCalculateCgroupCPU(t *testing.T) {
+	now := time.Now()
+	prev := CgroupCPUCounters{UsageUsec: 1_000_000, UserUsec: 600_000, SystemUsec: 400_000, NrPeriods: 100, Timestamp: now}
+	current := CgroupCPUCounters{
+		UsageUsec: 4_000_000, UserUsec: 2_600_000, SystemUsec: 1_400_000,
+		NrPeriods: 120, NrThrottled: 5, ThrottledUsec: 300_000, Timestamp: now.Add(2 * time.Second),
+	}
+
+	usage, user, system, throttledPercent, throttledTime := CalculateCgroupCPU(prev, current)
+	if math.Abs(usage-150.0) > 0.00001 || math.Abs(user-100.0) > 0.00001 || math.Abs(system-50.0) > 0.00001 ||
+		math.Abs(throttledPercent-25.0) > 0.00001 || math.Abs(throttledTime-150.0) > 0.00001 {
+		t.Errorf("CalculateCgroupCPU() = (%v, %v, %v, %v, %v), want (150, 100, 50, 25, 150)",
+			usage, user, system, throttledPercent, throttledTime)
+	}
+
+	if usage, _, _, _, _ := CalculateCgroupCPU(CgroupCPUCounters{}, current); usage != 0 {
+		t.Errorf("CalculateCgroupCPU() without baseline = %v, want 0", usage)
+	}
+}
+
+func TestCalculateCgroupIO(t *testing.T) {
+	now := time.Now()
+	prev := CgroupIOCounters{ReadBytes: 1000, WriteBytes: 2000, ReadIOs: 10, WriteIOs: 20, Timestamp: now}
+	current := CgroupIOCounters{ReadBytes: 5000, WriteBytes: 2000, ReadIOs: 18, WriteIOs: 20, Timestamp: now.Add(2 * time.Second)}
+
+	want := CgroupIOStats{ReadBytes: 2000, ReadIOPS: 4}
+	if got := CalculateCgroupIO(prev, current); got != want {
+		t.Errorf("CalculateCgroupIO() = %+v, want %+v", got, want)
+	}
+	reset := CgroupIOCounters{ReadBytes: 100, WriteBytes: 2000, ReadIOs: 1, WriteIOs: 20, Timestamp: now.Add(4 * time.Second)}
+	if got := CalculateCgroupIO(current, reset); got != (CgroupIOStats{}) {
+		t.Errorf("CalculateCgroupIO() with counter reset = %+v, want zero", got)
+	}
+}
+
+func TestCalculatePressureStall(t *testing.T) {
+	now := time.Now()
+	prev := PressureCounters{SomeTotal: 1_000_000, FullTotal: 500_000, HasFull: true, Timestamp: now}
+
+	tests := []struct {
+		name               string
+		prev               PressureCounters
+		current            PressureCounters
+		wantSome, wantFull float64
+	}{
+		{
+			name:     "Some And Full",
+			prev:     prev,
+			current:  PressureCounters{SomeTotal: 1_500_000, FullTotal: 600_000, HasFull: true, Timestamp: now.Add(2 * time.Second)},
+			wantSome: 25.0, wantFull: 5.0,
+		},
+		{
+			name:     "No Full Line",
+			prev:     PressureCounters{SomeTotal: 0, Timestamp: now},
+			current:  PressureCounters{SomeTotal: 100_000, Timestamp: now.Add(time.Second)},
+			wantSome: 10.0, wantFull: -1.0,
+		},
+		{
+			name:     "Clamped To Interval",
+			prev:     prev,
+			current:  PressureCounters{SomeTotal: 3_000_000, FullTotal: 500_000, HasFull: true, Timestamp: now.Add(time.Second)},
+			wantSome: 100.0, wantFull: 0.0,
+		},
+		{
+			name:     "No Baseline",
+			current:  PressureCounters{SomeTotal: 3_000_000, HasFull: true, Timestamp: now},
+			wantSome: 0.0, wantFull: -1.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			some, full := CalculatePressureStall(tt.prev, tt.current)
+			if math.Abs(some-tt.wantSome) > 0.00001 || math.Abs(full-tt.wantFull) > 0.00001 {
+				t.Errorf("CalculatePressureStall() = (%v, %v), want (%v, %v)", some, full, tt.wantSome, tt.wantFull)
+			}
+		})
+	}
+}
+
+func TestCalculateSystemRates(t *testing.T) {
+	now := time.Now()
+	prev := SystemCounters{ContextSwitches: 10_000, Interrupts: 4_000, Forks: 100, Timestamp: now}
+	current := SystemCounters{ContextSwitches: 30_000, Interrupts: 5_000, Forks: 104, Timestamp: now.Add(2 * time.Second)}
+
+	ctx, intr, forks := CalculateSystemRates(prev, current)
+	if math.Abs(ctx-10_000) > 0.00001 || math.Abs(intr-500) > 0.00001 || math.Abs(forks-2) > 0.00001 {
+		t.Errorf("CalculateSystemRates() = (%v, %v, %v), want (10000, 500, 2)", ctx, intr, forks)
+	}
+
+	if ctx, _, _ := CalculateSystemRates(SystemCounters{}, current); ctx != 0 {
+		t.Errorf("CalculateSystemRates() without baseline = %v, want 0", ctx)
+	}
+}
+
+func TestCalculateNetstatRates(t *testing.T) {
+	now := time.Now()
+	prev := NetstatCounters{ActiveOpens: 100, PassiveOpens: 200, RetransSegs: 1000, UDPInErrors: 5, Timestamp: now}
+	current := NetstatCounters{
+		ActiveOpens: 120, PassiveOpens: 260, RetransSegs: 1100, ListenOverflows: 4, ListenDrops: 6,
+		UDPInErrors: 9, UDPRcvbufErrors: 2, Timestamp: now.Add(2 * time.Second),
+	}
+
+	want := NetstatStats{
+		ActiveOpens: 10, PassiveOpens: 30, RetransSegs: 50, ListenOverflows: 2, ListenDrops: 3,
+		UDPInErrors: 2, UDPRcvbufErrors: 1,
+	}
+	if got := CalculateNetstatRates(prev, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("CalculateNetstatRates() = %+v, want %+v", got, want)
+	}
+	if got := CalculateNetstatRates(NetstatCounters{}, current); !reflect.DeepEqual(got, NetstatStats{}) {
+		t.Errorf("CalculateNetstatRates() without baseline = %+v, want zero", got)
+	}
+}
+
+func TestCounterDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev      uint64
+		current   uint64
+		wrap32    bool
+		wantDelta uint64
+		wantOK    bool
+	}{
+		{name: "Increase", prev: 100, current: 250, wantDelta: 150, wantOK: true},
+		{name: "Unchanged", prev: 100, current: 100, wantDelta: 0, wantOK: true},
+		{name: "Reset", prev: 5000, current: 10, wantDelta: 0, wantOK: false},
+		{name: "Reset With Wrap32 Above 32 Bits", prev: 1 << 40, current: 10, wrap32: true, wantDelta: 0, wantOK: false},
+		{name: "Wrap32", prev: math.MaxUint32 - 9, current: 5, wrap32: true, wantDelta: 15, wantOK: true},
+		{name: "Wrap32 Disabled", prev: math.MaxUint32 - 9, current: 5, wantDelta: 0, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta, ok := CounterDelta(tt.prev, tt.current, tt.wrap32)
+			if delta != tt.wantDelta || ok != tt.wantOK {
+				t.Errorf("CounterDelta(%d, %d, %v) = (%d, %v), want (%d, %v)",
+					tt.prev, tt.current, tt.wrap32, delta, ok, tt.wantDelta, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCountersReset(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name          string
+		prev          NetworkIOStats
+		current       NetworkIOStats
+		wantReset     bool
+		wantBandwidth float64
+	}{
+		{
+			name:          "Normal Increase",
+			prev:          NetworkIOStats{BytesRecv: 1000, PacketsRecv: 10, Timestamp: now},
+			current:       NetworkIOStats{BytesRecv: 2000, PacketsRecv: 20, Timestamp: now.Add(time.Second)},
+			wantBandwidth: 8000,
+		},
+		{
+			name:          "Interface Re-created",
+			prev:          NetworkIOStats{BytesRecv: 1 << 40, PacketsRecv: 1 << 30, Timestamp: now},
+			current:       NetworkIOStats{BytesRecv: 500, PacketsRecv: 5, Timestamp: now.Add(time.Second)},
+			wantReset:     true,
+			wantBandwidth: 0, // Never a bogus huge value
+		},
+		{
+			name:          "Single Counter Reset",
+			prev:          NetworkIOStats{BytesRecv: 1000, DropIn: 7, Timestamp: now},
+			current:       NetworkIOStats{BytesRecv: 2000, DropIn: 0, Timestamp: now.Add(time.Second)},
+			wantReset:     true,
+			wantBandwidth: 8000,
+		},
+		{
+			name:          "32-bit Wraparound",
+			prev:          NetworkIOStats{BytesRecv: math.MaxUint32 - 999, Counter32: true, Timestamp: now},
+			current:       NetworkIOStats{BytesRecv: 1000, Counter32: true, Timestamp: now.Add(time.Second)},
+			wantBandwidth: 16000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NetworkCountersReset(tt.prev, tt.current); got != tt.wantReset {
+				t.Errorf("NetworkCountersReset() = %v, want %v", got, tt.wantReset)
+			}
+			if got := CalculateNetworkBandwidth(tt.prev, tt.current); math.Abs(got-tt.wantBandwidth) > 0.00001 {
+				t.Errorf("CalculateNetworkBandwidth() = %v, want %v", got, tt.wantBandwidth)
+			}
+		})
+	}
+
+	// Disk counters follow the same rules
+	prevDisk := DiskIOStats{ReadCount: 100, IOTime: 5000, Timestamp: now}
+	resetDisk := DiskIOStats{ReadCount: 1, IOTime: 10, Timestamp: now.Add(time.Second)}
+	if !DiskCountersReset(prevDisk, resetDisk) {
+		t.Error("DiskCountersReset() = false, want true")
+	}
+	if got := CalculateDiskUtilization(prevDisk, resetDisk); got != 0 {
+		t.Errorf("CalculateDiskUtilization(reset) = %v, want 0", got)
+	}
+	if got := CalculateDiskIOPS(prevDisk, resetDisk); got != 0 {
+		t.Errorf("CalculateDiskIOPS(reset) = %v, want 0", got)
+	}
+
+	// So do the other cumulative sources, without 32-bit wraparound
+	others := []struct {
+		name          string
+		normal, reset bool
+	}{
+		{"Swap", SwapCountersReset(SwapIOStats{PagesIn: 10}, SwapIOStats{PagesIn: 20}),
+			SwapCountersReset(SwapIOStats{PagesOut: 10}, SwapIOStats{PagesOut: 2})},
+		{"Process", ProcessCountersReset(ProcessIOStats{CPUTime: 1.5, ReadBytes: 10}, ProcessIOStats{CPUTime: 2, ReadBytes: 10}),
+			ProcessCountersReset(ProcessIOStats{CPUTime: 2}, ProcessIOStats{CPUTime: 1.5})},
+		{"Cgroup CPU", CgroupCPUCountersReset(CgroupCPUCounters{UsageUsec: 10}, CgroupCPUCounters{UsageUsec: 10}),
+			CgroupCPUCountersReset(CgroupCPUCounters{UsageUsec: 10, NrPeriods: 5}, CgroupCPUCounters{UsageUsec: 20})},
+		{"Cgroup IO", CgroupIOCountersReset(CgroupIOCounters{ReadIOs: 1}, CgroupIOCounters{ReadIOs: 2}),
+			CgroupIOCountersReset(CgroupIOCounters{WriteBytes: math.MaxUint32}, CgroupIOCounters{WriteBytes: 1})},
+		{"System", SystemCountersReset(SystemCounters{Forks: 1}, SystemCounters{Forks: 3}),
+			SystemCountersReset(SystemCounters{Forks: 3}, SystemCounters{Forks: 1})},
+		{"Netstat", NetstatCountersReset(NetstatCounters{RetransSegs: 1}, NetstatCounters{RetransSegs: 1}),
+			NetstatCountersReset(NetstatCounters{ActiveOpens: 9}, NetstatCounters{ActiveOpens: 8})},
+		{"Pressure", PressureCountersReset(PressureCounters{SomeTotal: 5, FullTotal: 9}, PressureCounters{SomeTotal: 6, FullTotal: 1}),
+			PressureCountersReset(PressureCounters{SomeTotal: 5, FullTotal: 3, HasFull: true}, PressureCounters{SomeTotal: 6, FullTotal: 1, HasFull: true})},
+	}
+	for _, o := range others {
+		if o.normal || !o.reset {
+			t.Errorf("%s: reset on increase = %v, on decrease = %v; want false, true", o.name, o.normal, o.reset)
+		}
+	}
+}
+
 func TestCalculateEdgeCases(t *testing.T) {
 	// Test IsZero timestamp checks
 	emptyCPU := CPUTimeStats{}
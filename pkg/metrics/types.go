@@ -31,9 +31,71 @@ type Snapshot struct {
 	Timestamp time.Time
 	CPU       float64              // CPU utilization percentage
 	CPUWait   float64              // CPU iowait percentage (-1 if N/A)
+	CPUTimes  CPUBreakdown         // Share of each CPU time component
+	Cores     map[string]CoreStats // Key: core name (e.g. cpu0); nil unless per-CPU collection is enabled
 	Memory    float64              // Memory utilization percentage
+	MemDetail MemoryStats          // Memory breakdown and swap activity
 	Disks     map[string]DiskStats // Key: device name
 	Networks  map[string]NetStats  // Key: interface name
+
+	Filesystems map[string]FilesystemStats // Key: mountpoint
+	Processes   map[string]ProcessStats    // Key: process selector, e.g. "pid:1234", "name:nginx"
+
+	TopProcesses *TopProcesses // Heaviest processes of the interval (nil = not collected)
+
+	Cgroups map[string]CgroupStats // Key: cgroup path or container name
+
+	Pressure map[string]PressureStats // Key: resource ("cpu", "memory", "io")
+
+	System *SystemStats // Kernel activity counters (nil = not collected)
+
+	Netstat *NetstatStats // TCP/UDP protocol statistics (nil = not collected)
+
+	// CounterResets lists the sources whose counters were reset since the previous snapshot
+	// (e.g. "disk/sda", "network/eth0"). Their metrics are omitted from this snapshot.
+	CounterResets []string
+}
+
+// CPUBreakdown represents the share of each CPU time component as a percentage of total CPU time.
+type CPUBreakdown struct {
+	User    float64 // Time in user mode (includes guest time)
+	System  float64 // Time in kernel mode
+	Irq     float64 // Time servicing hardware interrupts
+	SoftIrq float64 // Time servicing software interrupts
+	Steal   float64 // Time stolen by the hypervisor for other virtual machines
+	Guest   float64 // Time running guest virtual machines (guest + guest_nice)
+}
+
+// CoreStats represents CPU metrics for a single logical core.
+type CoreStats struct {
+	Utilization float64 // CPU utilization percentage
+	IOWait      float64 // CPU iowait percentage (-1 if N/A)
+}
+
+// MaxCore returns the name and utilization of the busiest core.
+// ok is false when no per-core metrics were collected.
+func (s *Snapshot) MaxCore() (name string, utilization float64, ok bool) {
+	for core, stats := range s.Cores {
+		// Ties resolve to the lowest name so the result does not depend on map order
+		if !ok || stats.Utilization > utilization || (stats.Utilization == utilization && core < name) {
+			name, utilization, ok = core, stats.Utilization, true
+		}
+	}
+	return name, utilization, ok
+}
+
+// MemoryStats represents the memory breakdown and swap activity.
+type MemoryStats struct {
+	Available uint64  // Memory available for new allocations without swapping, in bytes
+	Cached    uint64  // Page cache in bytes
+	Buffers   uint64  // Block device buffers in bytes
+	Dirty     uint64  // Memory waiting to be written back to disk, in bytes
+	WriteBack uint64  // Memory actively being written back to disk, in bytes
+	Slab      uint64  // Kernel slab allocations in bytes
+	SwapUsed  uint64  // Used swap space in bytes
+	SwapTotal uint64  // Total swap space in bytes
+	SwapIn    float64 // Pages swapped in per second
+	SwapOut   float64 // Pages swapped out per second
 }
 
 // DiskStats represents disk I/O metrics for a single disk device.
@@ -41,11 +103,58 @@ type DiskStats struct {
 	Utilization float64 // Percentage of time disk was busy
 	Await       float64 // Average wait time for I/O operations in milliseconds
 	IOPS        float64 // Input/Output Operations Per Second
+
+	ReadIOPS        float64 // Completed reads per second (r/s)
+	WriteIOPS       float64 // Completed writes per second (w/s)
+	ReadThroughput  float64 // Bytes read per second
+	WriteThroughput float64 // Bytes written per second
+	ReadAwait       float64 // Average wait time of reads in milliseconds (r_await)
+	WriteAwait      float64 // Average wait time of writes in milliseconds (w_await)
+	AvgRequestSize  float64 // Average request size in bytes
+	QueueDepth      float64 // Average number of requests in flight (aqu-sz)
 }
 
 // NetStats represents network metrics for a single interface.
 type NetStats struct {
-	Bandwidth float64 // Network bandwidth in bits per second
+	Bandwidth float64 // Network bandwidth in bits per second (received + transmitted)
+
+	RxBandwidth float64 // Received bits per second
+	TxBandwidth float64 // Transmitted bits per second
+	RxPackets   float64 // Received packets per second
+	TxPackets   float64 // Transmitted packets per second
+	ErrIn       float64 // Receive errors per second
+	ErrOut      float64 // Transmit errors per second
+	DropIn      float64 // Dropped incoming packets per second
+	DropOut     float64 // Dropped outgoing packets per second
+}
+
+// FilesystemStats represents the capacity and inode usage of a mounted filesystem.
+type FilesystemStats struct {
+	Device string // Mounted device, e.g. /dev/sda1
+	Fstype string // Filesystem type, e.g. ext4
+
+	Total       uint64  // Total size in bytes
+	Used        uint64  // Used bytes
+	Free        uint64  // Bytes available to unprivileged users
+	UsedPercent float64 // Used space percentage
+
+	InodesTotal       uint64  // Total inodes (0 if the filesystem has no fixed inode table)
+	InodesUsed        uint64  // Used inodes
+	InodesFree        uint64  // Free inodes
+	InodesUsedPercent float64 // Used inodes percentage
+}
+
+// ProcessStats represents the resource usage of the processes matched by a selector.
+// When a selector matches several processes their usage is summed.
+type ProcessStats struct {
+	Count       int     // Number of matching processes
+	CPU         float64 // CPU utilization percentage (100% = one fully busy core)
+	RSS         uint64  // Resident set size in bytes
+	Threads     int64   // Number of threads
+	FDs         int64   // Number of open file descriptors
+	ReadBytes   float64 // Bytes read from storage per second
+	WriteBytes  float64 // Bytes written to storage per second
+	CtxSwitches float64 // Voluntary and involuntary context switches per second
 }
 
 // CPUTimeStats represents CPU time statistics for delta calculations.
@@ -66,15 +175,170 @@ type CPUTimeStats struct {
 type DiskIOStats struct {
 	ReadCount  uint64
 	WriteCount uint64
+	ReadBytes  uint64
+	WriteBytes uint64
 	ReadTime   uint64 // Milliseconds
 	WriteTime  uint64 // Milliseconds
 	IOTime     uint64 // Milliseconds disk was busy
+	WeightedIO uint64 // Milliseconds spent on I/O weighted by the number of requests in flight
+	Counter32  bool   // Counters are 32-bit and wrap around at 2^32
+	Timestamp  time.Time
+}
+
+// TopProcesses lists the heaviest processes of an interval, heaviest first.
+type TopProcesses struct {
+	ByCPU []TopProcess // Value: CPU utilization percentage (100% = one fully busy core)
+	ByRSS []TopProcess // Value: resident set size in bytes
+}
+
+// TopProcess is a process ranked by a single value.
+type TopProcess struct {
+	PID   int32
+	Name  string
+	Value float64
+}
+
+// CgroupStats represents the resource usage of a cgroup v2.
+type CgroupStats struct {
+	CPU              float64 // CPU utilization percentage (100% = one fully busy core)
+	CPUUser          float64 // User CPU utilization percentage
+	CPUSystem        float64 // System CPU utilization percentage
+	ThrottledPercent float64 // Percentage of enforcement periods in which the cgroup was throttled
+	ThrottledTime    float64 // Milliseconds throttled per second
+
+	MemoryCurrent uint64  // Memory usage in bytes
+	MemoryMax     uint64  // Memory limit in bytes (0 = unlimited)
+	MemoryPercent float64 // Usage percentage of the limit (-1 = unlimited)
+
+	PIDs uint64 // Number of tasks
+
+	IO map[string]CgroupIOStats // Key: device name, or major:minor if unknown
+}
+
+// CgroupIOStats represents the I/O rates of a cgroup on one device.
+type CgroupIOStats struct {
+	ReadBytes  float64 // Bytes read per second
+	WriteBytes float64 // Bytes written per second
+	ReadIOPS   float64 // Read operations per second
+	WriteIOPS  float64 // Write operations per second
+}
+
+// CgroupCPUCounters represents the cumulative cpu.stat counters of a cgroup for delta calculations.
+type CgroupCPUCounters struct {
+	UsageUsec     uint64
+	UserUsec      uint64
+	SystemUsec    uint64
+	NrPeriods     uint64
+	NrThrottled   uint64
+	ThrottledUsec uint64
+	Timestamp     time.Time
+}
+
+// CgroupIOCounters represents the cumulative io.stat counters of a cgroup on one device.
+type CgroupIOCounters struct {
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadIOs    uint64
+	WriteIOs   uint64
 	Timestamp  time.Time
 }
 
+// PressureStats represents the Pressure Stall Information (PSI) of a resource.
+// "Some" is the share of time at least one task stalled on the resource,
+// "full" the share of time all non-idle tasks stalled simultaneously.
+type PressureStats struct {
+	Some      float64 // Percentage of the interval with some tasks stalled
+	Full      float64 // Percentage of the interval with all tasks stalled (-1 = N/A)
+	SomeAvg10 float64 // Kernel 10s running average of Some
+	SomeAvg60 float64 // Kernel 60s running average of Some
+	FullAvg10 float64 // Kernel 10s running average of Full (-1 = N/A)
+	FullAvg60 float64 // Kernel 60s running average of Full (-1 = N/A)
+}
+
+// PressureCounters represents the cumulative stall times of a resource for delta calculations.
+type PressureCounters struct {
+	SomeTotal uint64 // Microseconds with some tasks stalled
+	FullTotal uint64 // Microseconds with all tasks stalled
+	HasFull   bool   // The kernel reports a "full" line for the resource
+	Timestamp time.Time
+}
+
+// SystemStats represents kernel scheduler activity.
+type SystemStats struct {
+	Load1           float64 // 1-minute load average
+	Load5           float64 // 5-minute load average
+	Load15          float64 // 15-minute load average
+	ContextSwitches float64 // Context switches per second
+	Interrupts      float64 // Interrupts per second
+	Forks           float64 // Processes created per second
+	ProcsRunning    uint64  // Runnable tasks
+	ProcsBlocked    uint64  // Tasks blocked on I/O
+}
+
+// SystemCounters represents the cumulative kernel activity counters of /proc/stat for delta calculations.
+type SystemCounters struct {
+	ContextSwitches uint64
+	Interrupts      uint64
+	Forks           uint64
+	Timestamp       time.Time
+}
+
+// TCPStates lists the TCP connection states in kernel order (state number - 1).
+var TCPStates = []string{
+	"ESTABLISHED", "SYN_SENT", "SYN_RECV", "FIN_WAIT1", "FIN_WAIT2", "TIME_WAIT",
+	"CLOSE", "CLOSE_WAIT", "LAST_ACK", "LISTEN", "CLOSING", "NEW_SYN_RECV",
+}
+
+// NetstatStats represents TCP/UDP protocol statistics.
+type NetstatStats struct {
+	TCPStates       map[string]uint64 // Connections by state, key: one of TCPStates
+	ActiveOpens     float64           // Outgoing connections opened per second
+	PassiveOpens    float64           // Incoming connections accepted per second
+	RetransSegs     float64           // Retransmitted segments per second
+	ListenOverflows float64           // Connections dropped per second because an accept queue was full
+	ListenDrops     float64           // Connections dropped per second at listening sockets for any reason
+	UDPInErrors     float64           // Undeliverable UDP datagrams received per second
+	UDPRcvbufErrors float64           // UDP datagrams dropped per second because a receive buffer was full
+}
+
+// NetstatCounters represents the cumulative protocol counters for delta calculations.
+type NetstatCounters struct {
+	ActiveOpens     uint64
+	PassiveOpens    uint64
+	RetransSegs     uint64
+	ListenOverflows uint64
+	ListenDrops     uint64
+	UDPInErrors     uint64
+	UDPRcvbufErrors uint64
+	Timestamp       time.Time
+}
+
+// ProcessIOStats represents the cumulative counters of a single process for delta calculations.
+type ProcessIOStats struct {
+	CPUTime     float64 // User + system CPU time in seconds
+	ReadBytes   uint64
+	WriteBytes  uint64
+	CtxSwitches uint64 // Voluntary + involuntary context switches
+	Timestamp   time.Time
+}
+
+// SwapIOStats represents swap activity counters for delta calculations.
+type SwapIOStats struct {
+	PagesIn   uint64 // Pages swapped in since boot
+	PagesOut  uint64 // Pages swapped out since boot
+	Timestamp time.Time
+}
+
 // NetworkIOStats represents network I/O counters for delta calculations.
 type NetworkIOStats struct {
-	BytesSent uint64
-	BytesRecv uint64
-	Timestamp time.Time
+	BytesSent   uint64
+	BytesRecv   uint64
+	PacketsSent uint64
+	PacketsRecv uint64
+	ErrIn       uint64
+	ErrOut      uint64
+	DropIn      uint64
+	DropOut     uint64
+	Counter32   bool // Counters are 32-bit and wrap around at 2^32
+	Timestamp   time.Time
 }
@@ -0,0 +1,44 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Nguyen Thanh Phuong
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metrics
+
+import "testing"
+
+func TestSnapshot_MaxCore(t *testing.T) {
+	if _, _, ok := (&Snapshot{}).MaxCore(); ok {
+		t.Error("MaxCore() without cores should report ok = false")
+	}
+
+	s := &Snapshot{Cores: map[string]CoreStats{
+		"cpu0": {Utilization: 12.5},
+		"cpu1": {Utilization: 99.0},
+		"cpu2": {Utilization: 99.0},
+		"cpu3": {Utilization: 3.0},
+	}}
+	name, util, ok := s.MaxCore()
+	if !ok || name != "cpu1" || util != 99.0 {
+		t.Errorf("MaxCore() = (%q, %v, %v), want (\"cpu1\", 99, true)", name, util, ok)
+	}
+}
@@ -12,6 +12,10 @@ let appSettings = {
 
 let activeFileId = null;
 
+// Top processes per file and timestamp for chart tooltips (null = none recorded)
+let topProcessesCache = {};
+let topProcessesUnavailable = {};
+
 // Initialize app
 document.addEventListener('DOMContentLoaded', () => {
     loadSettings();
@@ -182,7 +186,7 @@ async function handleFileSelect(e) {
 
     let successCount = 0;
     for (const file of selectedFiles) {
-        if (file.name.endsWith('.csv')) {
+        if (file.name.endsWith('.csv') || file.name.endsWith('.csv.gz')) {
             const success = await uploadFile(file);
             if (success) successCount++;
         } else {
@@ -558,6 +562,10 @@ async function renderChart(file, metric, chartId) {
                         callbacks: {
                             label: function (context) {
                                 return `Value: ${context.parsed.y.toFixed(2)}`;
+                            },
+                            afterBody: function (items) {
+                                if (items.length === 0) return [];
+                                return topProcessesLines(file.id, items[0].raw.x, items[0].chart);
                             }
                         }
                     },
@@ -985,6 +993,49 @@ function getMetricColor(metric) {
     return '#' + "00000".substring(0, 6 - c.length) + c;
 }
 
+// topProcessesLines returns the tooltip lines listing the heaviest processes at a timestamp.
+// The ranking is fetched on first hover; the tooltip is refreshed once it arrives.
+function topProcessesLines(fileId, timestamp, chart) {
+    if (topProcessesUnavailable[fileId]) return [];
+
+    const key = `${fileId}|${timestamp}`;
+    if (!(key in topProcessesCache)) {
+        topProcessesCache[key] = undefined; // Pending
+        fetch(`/api/files/${fileId}/top?timestamp=${encodeURIComponent(timestamp)}`)
+            .then(response => {
+                if (response.status === 404) {
+                    topProcessesUnavailable[fileId] = true;
+                    return null;
+                }
+                return response.ok ? response.json() : null;
+            })
+            .then(top => {
+                topProcessesCache[key] = top;
+                const active = chart.tooltip ? chart.tooltip.getActiveElements() : [];
+                if (top && active.length > 0) {
+                    chart.tooltip.setActiveElements(active, { x: chart.tooltip.caretX, y: chart.tooltip.caretY });
+                    chart.update('none');
+                }
+            })
+            .catch(() => { topProcessesCache[key] = null; });
+        return [];
+    }
+
+    const top = topProcessesCache[key];
+    if (!top) return [];
+
+    const lines = [];
+    if (top.by_cpu && top.by_cpu.length > 0) {
+        lines.push('', 'Top CPU:');
+        top.by_cpu.forEach(p => lines.push(`  ${p.name} (${p.pid}): ${p.value.toFixed(1)}%`));
+    }
+    if (top.by_rss && top.by_rss.length > 0) {
+        lines.push('', 'Top RSS:');
+        top.by_rss.forEach(p => lines.push(`  ${p.name} (${p.pid}): ${(p.value / (1024 * 1024)).toFixed(1)} MB`));
+    }
+    return lines;
+}
+
 function hexToRgba(hex, alpha) {
     const r = parseInt(hex.slice(1, 3), 16);
     const g = parseInt(hex.slice(3, 5), 16);
@@ -1049,6 +1100,8 @@ async function clearAllData() {
             // Reset local state
             files = [];
             charts = {};
+            topProcessesCache = {};
+            topProcessesUnavailable = {};
             loadFiles(); // Should return empty list
         } else {
             throw new Error('Server delete failed');